@@ -0,0 +1,151 @@
+package ffr
+
+import "testing"
+
+func TestConvertTemplatePassesThroughAttributes(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:     "Keys without special handling follow in source order",
+			input:    `{{ Form::text('email', null, ['maxlength' => 255, 'class' => 'form-control', 'autocomplete' => 'email', 'aria-describedby' => 'email-help', 'title' => '', 'tabindex' => 2]) }}`,
+			expected: `<input type="text" name="email" value="" class="form-control" maxlength="255" autocomplete="email" aria-describedby="email-help" title="" tabindex="2">`,
+		},
+		{
+			name:     "Keys already written by the element are not repeated",
+			input:    `{{ Form::text('email', null, ['name' => 'other', 'type' => 'email', 'value' => 'x', 'inputmode' => 'email']) }}`,
+			expected: `<input type="text" name="email" value="" inputmode="email">`,
+		},
+		{
+			name:     "Keys differing only in case from ordered keys are not repeated",
+			input:    `{{ Form::select('size', $sizes, null, ['onChange' => 'go()', 'form' => 'filters']) }}`,
+			expected: "<select name=\"size\" onchange=\"go()\" form=\"filters\">\n@foreach($sizes as $key => $value)\n<option value=\"{{ $key }}\">{{ $value }}</option>\n@endforeach\n</select>",
+		},
+		{
+			name:     "Label keeps for on the tag",
+			input:    `{{ Form::label('email', 'E-mail', ['for' => 'mail', 'title' => 'Mail']) }}`,
+			expected: `<label for="mail" title="Mail">{!! 'E-mail' !!}</label>`,
+		},
+		{
+			name:     "Form options are not written as attributes",
+			input:    `{!! Form::open(['url' => '/search', 'method' => 'GET', 'role' => 'search', 'files' => true]) !!}`,
+			expected: `<form action="{{ url('/search') }}" method="GET" enctype="multipart/form-data" role="search">`,
+		},
+		{
+			name:     "Checkbox data attributes are written once",
+			input:    `{{ Form::checkbox('agree', 1, false, ['data-id' => '7', 'title' => 'Agree']) }}`,
+			expected: `<input type="checkbox" name="agree" value="1" data-id="7" title="Agree">`,
+		},
+		{
+			name:     "Keys that cannot be attribute names are dropped",
+			input:    `{{ Form::text('q', null, ['a b' => 'x', '"x' => 'y', 'class' => 'c']) }}`,
+			expected: `<input type="text" name="q" value="" class="c">`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got, _ := convertTemplate(tt.input); got != tt.expected {
+				t.Errorf("convertTemplate() =\n%s\nwant:\n%s", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestConvertTemplateBooleanAttributes(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:     "Keys without values",
+			input:    `{{ Form::text('email', null, ['class' => 'c', 'required', 'autofocus']) }}`,
+			expected: `<input type="text" name="email" value="" class="c" required autofocus>`,
+		},
+		{
+			name:     "Integer keys",
+			input:    `{{ Form::email('email', null, [0 => 'readonly']) }}`,
+			expected: `<input type="email" name="email" value="" readonly>`,
+		},
+		{
+			name:     "Key without value in the configured order",
+			input:    `{{ Form::text('email', null, ['required', 'class' => 'c']) }}`,
+			expected: `<input type="text" name="email" value="" class="c" required>`,
+		},
+		{
+			name:     "Literal true, false and null",
+			input:    `{{ Form::text('email', null, ['readonly' => true, 'autofocus' => false, 'required' => null, 'title' => null]) }}`,
+			expected: `<input type="text" name="email" value="" readonly>`,
+		},
+		{
+			name:     "Value equal to the attribute name",
+			input:    `{{ Form::textarea('body', null, ['readonly' => 'readonly', 'hidden' => '']) }}`,
+			expected: `<textarea name="body" readonly hidden></textarea>`,
+		},
+		{
+			name:     "Expression values become conditional",
+			input:    `{{ Form::text('email', null, ['readonly' => $locked, 'disabled' => !$user->can('edit')]) }}`,
+			expected: `<input type="text" name="email" value="" @if($locked) readonly @endif @if(!$user->can('edit')) disabled @endif>`,
+		},
+		{
+			name:     "Other elements",
+			input:    `{{ Form::select('tags[]', $tags, null, ['multiple' => true, 'required' => $mustChoose]) }}`,
+			expected: "<select name=\"tags[]\" multiple @if($mustChoose) required @endif>\n@foreach($tags as $key => $value)\n<option value=\"{{ $key }}\">{{ $value }}</option>\n@endforeach\n</select>",
+		},
+		{
+			name:     "File multiple from an expression",
+			input:    `{{ Form::file('photos[]', ['multiple' => $many, 'class' => 'f']) }}`,
+			expected: `<input type="file" name="photos[]" class="f" @if($many) multiple @endif>`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got, _ := convertTemplate(tt.input); got != tt.expected {
+				t.Errorf("convertTemplate() =\n%s\nwant:\n%s", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestConvertTemplateExpressionAttributes(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:     "Ternary class",
+			input:    `{{ Form::email('email', null, ['class' => $errors->has('email') ? 'form-control is-invalid' : 'form-control']) }}`,
+			expected: `<input type="email" name="email" value="" class="{{ $errors->has('email') ? 'form-control is-invalid' : 'form-control' }}">`,
+		},
+		{
+			name:     "Translated placeholder",
+			input:    `{{ Form::text('email', null, ['placeholder' => __('auth.email'), 'title' => trans('auth.hint')]) }}`,
+			expected: `<input type="text" name="email" value="" placeholder="{{ __('auth.email') }}" title="{{ trans('auth.hint') }}">`,
+		},
+		{
+			name:     "Number bounds from variables",
+			input:    `{{ Form::number('qty', 1, ['min' => 0, 'max' => $limit, 'step' => $product->step]) }}`,
+			expected: `<input type="number" name="qty" value="{{ 1 }}" min="0" max="{{ $limit }}" step="{{ $product->step }}">`,
+		},
+		{
+			name:     "Concatenation and constants",
+			input:    `{{ Form::textarea('body', null, ['class' => 'editor ' . $theme, 'maxlength' => Post::MAX_LENGTH]) }}`,
+			expected: `<textarea name="body" class="{{ 'editor ' . $theme }}" maxlength="{{ Post::MAX_LENGTH }}"></textarea>`,
+		},
+		{
+			name:     "Literals stay static",
+			input:    `{{ Form::text('code', null, ['pattern' => '[A-Z]{3}', 'size' => 3]) }}`,
+			expected: `<input type="text" name="code" value="" pattern="[A-Z]{3}" size="3">`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got, _ := convertTemplate(tt.input); got != tt.expected {
+				t.Errorf("convertTemplate() =\n%s\nwant:\n%s", got, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,326 @@
+// backup.go: 変換前の内容のバックアップ（.ffr-backup/<timestamp>/）と restore サブコマンド。
+package ffr
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// バックアップの既定ディレクトリとマニフェストのファイル名
+const (
+	defaultBackupDir   = ".ffr-backup"
+	backupManifestName = "manifest.json"
+	backupIDLayout     = "20060102-150405"
+)
+
+// backupManifest は1回の実行で書き換えたファイルの一覧（manifest.json）。
+type backupManifest struct {
+	ID        string        `json:"id"`
+	CreatedAt string        `json:"created_at"`
+	Target    string        `json:"target"`
+	Files     []backupEntry `json:"files"`
+}
+
+// backupEntry は書き換えた1ファイル分の記録。
+type backupEntry struct {
+	Path         string `json:"path"`   // 書き換えたファイルの絶対パス
+	Backup       string `json:"backup"` // バックアップディレクトリ内の元の内容（相対パス）
+	OriginalHash string `json:"original_sha256"`
+	NewHash      string `json:"new_sha256"`
+}
+
+// backupRun は実行中のバックアップ先（.ffr-backup/<timestamp>/）を表す。
+type backupRun struct {
+	Dir      string
+	Manifest backupManifest
+}
+
+// contentHash は内容の SHA-256 を16進文字列で返す。
+func contentHash(content string) string {
+	sum := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(sum[:])
+}
+
+// newBackupRun は baseDir 配下にタイムスタンプ名のバックアップディレクトリを作成する。
+func newBackupRun(baseDir, target string, now time.Time) (*backupRun, error) {
+	id := now.Format(backupIDLayout)
+	dir := filepath.Join(baseDir, id)
+	// 同じ秒に複数回実行された場合は連番を付ける
+	for n := 2; ; n++ {
+		if _, err := os.Stat(dir); os.IsNotExist(err) {
+			break
+		}
+		id = fmt.Sprintf("%s-%d", now.Format(backupIDLayout), n)
+		dir = filepath.Join(baseDir, id)
+	}
+	if err := os.MkdirAll(filepath.Join(dir, "files"), 0755); err != nil {
+		return nil, err
+	}
+	if abs, err := filepath.Abs(target); err == nil {
+		target = abs
+	}
+	return &backupRun{
+		Dir: dir,
+		Manifest: backupManifest{
+			ID:        id,
+			CreatedAt: now.Format(time.RFC3339),
+			Target:    target,
+			Files:     make([]backupEntry, 0),
+		},
+	}, nil
+}
+
+// record は書き換え前の内容を保存し、マニフェストを更新する。
+// 途中で中断されても記録済みのファイルは復元できるよう、1ファイルごとにマニフェストを書き出す。
+func (b *backupRun) record(result *FileResult) error {
+	path, err := filepath.Abs(result.Path)
+	if err != nil {
+		return err
+	}
+	// .blade.php のまま保存すると再実行時に変換対象となるため .orig で保存する
+	backupName := filepath.ToSlash(filepath.Join("files", fmt.Sprintf("%04d.orig", len(b.Manifest.Files)+1)))
+	if err := os.WriteFile(filepath.Join(b.Dir, backupName), []byte(result.Original), 0644); err != nil {
+		return err
+	}
+	b.Manifest.Files = append(b.Manifest.Files, backupEntry{
+		Path:         path,
+		Backup:       backupName,
+		OriginalHash: contentHash(result.Original),
+		NewHash:      contentHash(result.Converted),
+	})
+	return b.saveManifest()
+}
+
+// forget は count 件より後の記録を取り消す（書き込まなかった、または元に戻したファイルの分）。
+func (b *backupRun) forget(count int) error {
+	for _, entry := range b.Manifest.Files[count:] {
+		if err := os.Remove(filepath.Join(b.Dir, filepath.FromSlash(entry.Backup))); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	b.Manifest.Files = b.Manifest.Files[:count]
+	return b.saveManifest()
+}
+
+// saveManifest は manifest.json を書き出す。
+func (b *backupRun) saveManifest() error {
+	data, err := json.MarshalIndent(b.Manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(b.Dir, backupManifestName), append(data, '\n'), 0644)
+}
+
+// backupResult は書き換え前に変換結果をバックアップへ記録する（初回にディレクトリを作成）。
+// 変更チェック（verifyUnchanged）の後に呼び、書き込まないファイルを記録しない。
+func backupResult(config *ReplacementConfig, result *FileResult) error {
+	if config.backup == nil {
+		run, err := newBackupRun(config.BackupDir, config.TargetPath, time.Now())
+		if err != nil {
+			return fmt.Errorf("バックアップディレクトリの作成に失敗しました: %v", err)
+		}
+		config.backup = run
+	}
+	if err := config.backup.record(result); err != nil {
+		return fmt.Errorf("バックアップの保存に失敗しました: %v", err)
+	}
+	return nil
+}
+
+// backupCount は記録済みのバックアップの件数を返す（まだ作成していなければ 0）。
+func backupCount(config *ReplacementConfig) int {
+	if config.backup == nil {
+		return 0
+	}
+	return len(config.backup.Manifest.Files)
+}
+
+// forgetBackups は書き込みに失敗したファイルのバックアップの記録を取り消し、元のエラーを返す。
+func forgetBackups(config *ReplacementConfig, recorded int, cause error) error {
+	if config.backup == nil {
+		return cause
+	}
+	if err := config.backup.forget(recorded); err != nil {
+		return fmt.Errorf("%v（さらにバックアップの記録の取り消しに失敗しました: %v）", cause, err)
+	}
+	return cause
+}
+
+// loadBackupManifest は baseDir/<id>/manifest.json を読み込む。
+func loadBackupManifest(baseDir, id string) (*backupManifest, error) {
+	data, err := os.ReadFile(filepath.Join(baseDir, id, backupManifestName))
+	if err != nil {
+		return nil, err
+	}
+	var manifest backupManifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("マニフェストの解析に失敗しました: %v", err)
+	}
+	return &manifest, nil
+}
+
+// listBackupRuns は baseDir 配下のバックアップID一覧を古い順で返す。
+func listBackupRuns(baseDir string) ([]string, error) {
+	entries, err := os.ReadDir(baseDir)
+	if err != nil {
+		return nil, err
+	}
+	var ids []string
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		if _, err := os.Stat(filepath.Join(baseDir, entry.Name(), backupManifestName)); err == nil {
+			ids = append(ids, entry.Name())
+		}
+	}
+	sort.Strings(ids)
+	return ids, nil
+}
+
+// restoreConflict は復元を拒否したファイルとその理由。
+type restoreConflict struct {
+	Path   string
+	Reason string
+}
+
+// restoreBackup はバックアップの内容でファイルを元に戻す。
+// 変換後に編集されたファイル（ハッシュが一致しない）が1つでもあれば、force でない限り何も書き換えない。
+func restoreBackup(baseDir, id string, force bool) ([]string, []restoreConflict, error) {
+	manifest, err := loadBackupManifest(baseDir, id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var conflicts []restoreConflict
+	for _, entry := range manifest.Files {
+		current, err := os.ReadFile(entry.Path)
+		switch {
+		case os.IsNotExist(err):
+			conflicts = append(conflicts, restoreConflict{entry.Path, "ファイルが削除されています"})
+		case err != nil:
+			return nil, nil, err
+		case contentHash(string(current)) != entry.NewHash:
+			conflicts = append(conflicts, restoreConflict{entry.Path, "変換後に編集されています"})
+		}
+	}
+	if len(conflicts) > 0 && !force {
+		return nil, conflicts, nil
+	}
+
+	var restored []string
+	for _, entry := range manifest.Files {
+		original, err := os.ReadFile(filepath.Join(baseDir, id, filepath.FromSlash(entry.Backup)))
+		if err != nil {
+			return restored, conflicts, err
+		}
+		if contentHash(string(original)) != entry.OriginalHash {
+			return restored, conflicts, fmt.Errorf("バックアップ %s が破損しています", entry.Backup)
+		}
+		if err := writeFileAtomic(entry.Path, original, fileMode(entry.Path)); err != nil {
+			return restored, conflicts, err
+		}
+		restored = append(restored, entry.Path)
+	}
+	return restored, conflicts, nil
+}
+
+// runRestore は restore サブコマンドのエントリポイント。戻り値はプロセス終了コード。
+func runRestore(args []string, stdout io.Writer, logger *log.Logger) int {
+	baseDir := defaultBackupDir
+	force := false
+	list := false
+	id := ""
+	for i := 0; i < len(args); i++ {
+		name, value, hasValue := args[i], "", false
+		if strings.HasPrefix(name, "--") {
+			name, value, hasValue = strings.Cut(name, "=")
+		}
+		switch name {
+		case "-h", "--help":
+			printRestoreUsage(stdout)
+			return 0
+		case "--backup-dir":
+			v, err := optionValue(args, &i, name, value, hasValue)
+			if err != nil {
+				fmt.Fprintf(stdout, "エラー: %v\n", err)
+				return 1
+			}
+			baseDir = v
+		case "--force":
+			force = true
+		case "--list":
+			list = true
+		default:
+			if id != "" || strings.HasPrefix(name, "-") {
+				fmt.Fprintf(stdout, "エラー: 不明な引数です: %s\n", args[i])
+				printRestoreUsage(stdout)
+				return 1
+			}
+			id = name
+		}
+	}
+
+	ids, err := listBackupRuns(baseDir)
+	if err != nil {
+		logger.Printf("エラー: バックアップディレクトリ '%s' を読み込めません: %v", baseDir, err)
+		return 1
+	}
+	if list || id == "" {
+		if len(ids) == 0 {
+			fmt.Fprintf(stdout, "バックアップはありません: %s\n", baseDir)
+			return 0
+		}
+		fmt.Fprintf(stdout, "=== バックアップ一覧 (%s) ===\n", baseDir)
+		for _, runID := range ids {
+			if manifest, err := loadBackupManifest(baseDir, runID); err == nil {
+				fmt.Fprintf(stdout, "%s  %d ファイル  %s\n", runID, len(manifest.Files), manifest.Target)
+			}
+		}
+		return 0
+	}
+	if id == "latest" && len(ids) > 0 {
+		id = ids[len(ids)-1]
+	}
+
+	restored, conflicts, err := restoreBackup(baseDir, id, force)
+	if len(conflicts) > 0 {
+		fmt.Fprintln(stdout, "=== 変換後に変更されたファイル ===")
+		for _, conflict := range conflicts {
+			fmt.Fprintf(stdout, "%s: %s\n", conflict.Path, conflict.Reason)
+		}
+		fmt.Fprintln(stdout)
+	}
+	for _, path := range restored {
+		fmt.Fprintf(stdout, "復元しました: %s\n", path)
+	}
+	if err != nil {
+		logger.Printf("エラー: バックアップ %s の復元に失敗しました: %v", id, err)
+		return 1
+	}
+	if len(conflicts) > 0 && !force {
+		fmt.Fprintln(stdout, "変換後に変更されたファイルがあるため復元を中止しました（上書きする場合は --force を指定してください）")
+		return 1
+	}
+	fmt.Fprintf(stdout, "バックアップ %s から %d ファイルを復元しました\n", id, len(restored))
+	return 0
+}
+
+// printRestoreUsage は restore サブコマンドの使用方法を表示する。
+func printRestoreUsage(w io.Writer) {
+	fmt.Fprintln(w, "使用方法: go run form_facade_replacer.go restore [オプション] [<バックアップID>|latest]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "オプション:")
+	fmt.Fprintln(w, " --backup-dir <ディレクトリ> バックアップの保存先（既定: .ffr-backup）")
+	fmt.Fprintln(w, " --list バックアップの一覧を表示")
+	fmt.Fprintln(w, " --force 変換後に編集されたファイルも上書きして復元する")
+}
@@ -0,0 +1,172 @@
+package ffr
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// setupBackupTest は変換対象ファイルを作成し、バックアップ付きで変換を実行する。
+func setupBackupTest(t *testing.T) (viewsDir, backupDir string, files map[string]string) {
+	t.Helper()
+	tempDir := t.TempDir()
+	viewsDir = filepath.Join(tempDir, "views")
+	backupDir = filepath.Join(tempDir, ".ffr-backup")
+	if err := os.MkdirAll(viewsDir, 0755); err != nil {
+		t.Fatalf("Failed to create views dir: %v", err)
+	}
+	files = map[string]string{
+		"create.blade.php": "{!! Form::text('name') !!}\n",
+		"edit.blade.php":   "{{ Form::hidden('id', $user->id) }}\n",
+		"plain.blade.php":  "<p>no form</p>\n",
+	}
+	for filename, content := range files {
+		if err := os.WriteFile(filepath.Join(viewsDir, filename), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to create test file %s: %v", filename, err)
+		}
+	}
+
+	config := newReplacementConfig()
+	config.TargetPath = viewsDir
+	config.Backup = true
+	config.BackupDir = backupDir
+	if err := processBladeFiles(config); err != nil {
+		t.Fatalf("Failed to process directory: %v", err)
+	}
+	if config.backup == nil {
+		t.Fatal("expected a backup run to be created")
+	}
+	if len(config.backup.Manifest.Files) != 2 {
+		t.Fatalf("expected 2 backed up files, got %d", len(config.backup.Manifest.Files))
+	}
+	return viewsDir, backupDir, files
+}
+
+func TestBackupAndRestore(t *testing.T) {
+	viewsDir, backupDir, files := setupBackupTest(t)
+
+	ids, err := listBackupRuns(backupDir)
+	if err != nil || len(ids) != 1 {
+		t.Fatalf("listBackupRuns() = %v, %v; want one run", ids, err)
+	}
+
+	converted, _ := os.ReadFile(filepath.Join(viewsDir, "create.blade.php"))
+	if string(converted) == files["create.blade.php"] {
+		t.Fatal("expected create.blade.php to be converted")
+	}
+
+	restored, conflicts, err := restoreBackup(backupDir, ids[0], false)
+	if err != nil {
+		t.Fatalf("restoreBackup() error: %v", err)
+	}
+	if len(conflicts) != 0 || len(restored) != 2 {
+		t.Errorf("restoreBackup() restored %v, conflicts %v", restored, conflicts)
+	}
+	for filename, content := range files {
+		result, _ := os.ReadFile(filepath.Join(viewsDir, filename))
+		if string(result) != content {
+			t.Errorf("%s was not restored:\n%s", filename, string(result))
+		}
+	}
+}
+
+func TestRestoreRefusesEditedFiles(t *testing.T) {
+	viewsDir, backupDir, files := setupBackupTest(t)
+	ids, _ := listBackupRuns(backupDir)
+
+	// 変換後にファイルを編集する
+	edited := filepath.Join(viewsDir, "edit.blade.php")
+	if err := os.WriteFile(edited, []byte("<p>edited by hand</p>\n"), 0644); err != nil {
+		t.Fatalf("Failed to edit file: %v", err)
+	}
+
+	restored, conflicts, err := restoreBackup(backupDir, ids[0], false)
+	if err != nil {
+		t.Fatalf("restoreBackup() error: %v", err)
+	}
+	if len(restored) != 0 {
+		t.Errorf("expected no files to be restored, got %v", restored)
+	}
+	if len(conflicts) != 1 || conflicts[0].Path != edited {
+		t.Errorf("unexpected conflicts: %+v", conflicts)
+	}
+	// 編集されていないファイルも含め、何も書き換えていないこと
+	result, _ := os.ReadFile(filepath.Join(viewsDir, "create.blade.php"))
+	if string(result) == files["create.blade.php"] {
+		t.Error("create.blade.php should not be restored when another file conflicts")
+	}
+
+	// --force では上書きして復元する
+	restored, _, err = restoreBackup(backupDir, ids[0], true)
+	if err != nil || len(restored) != 2 {
+		t.Fatalf("forced restoreBackup() = %v, %v", restored, err)
+	}
+	result, _ = os.ReadFile(edited)
+	if string(result) != files["edit.blade.php"] {
+		t.Errorf("edit.blade.php was not restored with force:\n%s", string(result))
+	}
+}
+
+func TestRunRestoreSubcommand(t *testing.T) {
+	_, backupDir, _ := setupBackupTest(t)
+
+	if code := Run([]string{"form-facade-replacer", "restore", "--backup-dir", backupDir, "--list"}); code != 0 {
+		t.Errorf("restore --list exit code = %d, want 0", code)
+	}
+	if code := Run([]string{"form-facade-replacer", "restore", "--backup-dir", backupDir, "latest"}); code != 0 {
+		t.Errorf("restore latest exit code = %d, want 0", code)
+	}
+	if code := Run([]string{"form-facade-replacer", "restore", "--backup-dir", backupDir, "no-such-run"}); code != 1 {
+		t.Errorf("restore of unknown run exit code = %d, want 1", code)
+	}
+}
+
+func TestBackupSkipsFilesChangedAfterReading(t *testing.T) {
+	tempDir := t.TempDir()
+	testFile := filepath.Join(tempDir, "form.blade.php")
+	if err := os.WriteFile(testFile, []byte("{{ Form::text('name') }}"), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+	result, err := convertFile(testFile)
+	if err != nil {
+		t.Fatalf("convertFile() error: %v", err)
+	}
+	// 読み込み後に別のプロセスが書き換えた状況
+	if err := os.WriteFile(testFile, []byte("edited"), 0644); err != nil {
+		t.Fatalf("Failed to edit file: %v", err)
+	}
+
+	config := newReplacementConfig()
+	config.TargetPath = tempDir
+	config.Backup = true
+	config.BackupDir = filepath.Join(tempDir, ".ffr-backup")
+	if err := writeResultWithBackup(config, result); err == nil {
+		t.Fatal("writeResultWithBackup() should fail when the file changed after reading")
+	}
+	if n := backupCount(config); n != 0 {
+		t.Errorf("expected no backup entries, got %d", n)
+	}
+}
+
+func TestBackupForgetRemovesEntries(t *testing.T) {
+	_, backupDir, _ := setupBackupTest(t)
+	ids, _ := listBackupRuns(backupDir)
+	run := &backupRun{Dir: filepath.Join(backupDir, ids[0])}
+	manifest, err := loadBackupManifest(backupDir, ids[0])
+	if err != nil {
+		t.Fatalf("loadBackupManifest() error: %v", err)
+	}
+	run.Manifest = *manifest
+	forgotten := run.Manifest.Files[1]
+
+	if err := run.forget(1); err != nil {
+		t.Fatalf("forget() error: %v", err)
+	}
+	manifest, err = loadBackupManifest(backupDir, ids[0])
+	if err != nil || len(manifest.Files) != 1 {
+		t.Fatalf("manifest after forget() = %+v, %v; want one entry", manifest, err)
+	}
+	if _, err := os.Stat(filepath.Join(run.Dir, filepath.FromSlash(forgotten.Backup))); !os.IsNotExist(err) {
+		t.Errorf("backup file %s was not removed", forgotten.Backup)
+	}
+}
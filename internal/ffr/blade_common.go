@@ -5,118 +5,279 @@ import (
 	"strings"
 )
 
+// attrValue は属性値として受け付ける PHP のリテラルの種類（リテラル以外の式はどの種類でも {{ }} で出力する）。
+type attrValue int
+
+const (
+	attrText    attrValue = iota // 空でない文字列リテラル、または数値リテラル
+	attrLiteral                  // 文字列リテラル（空文字も可）、または数値リテラル
+)
+
 // 属性処理
+// Order のキーを先にその順で出力し、残りのキーはオプション配列の記述順にそのまま属性として出力する。
+// Values にないキーの値は attrLiteral として扱う。Skip のキーは要素側で扱うため出力しない
+// （"data-" のように - で終わるものは前方一致）。
 type AttributeProcessor struct {
-	Order    []string
-	Patterns map[string]string
+	Order  []string
+	Values map[string]attrValue
+	Skip   []string
+}
+
+// booleanAttributes は値を持たずに出力する HTML の真偽値属性。
+var booleanAttributes = map[string]bool{
+	"allowfullscreen": true, "async": true, "autofocus": true, "autoplay": true, "checked": true,
+	"controls": true, "default": true, "defer": true, "disabled": true, "formnovalidate": true,
+	"hidden": true, "inert": true, "ismap": true, "itemscope": true, "loop": true,
+	"multiple": true, "muted": true, "nomodule": true, "novalidate": true, "open": true,
+	"playsinline": true, "readonly": true, "required": true, "reversed": true, "selected": true,
 }
 
+// ProcessAttributes はオプション配列（[...] または括弧の内側）の属性を出力する。
+// ['required', 'autofocus'] のようにキーのない要素は、その値を値なしの属性として出力する。
 func (ap *AttributeProcessor) ProcessAttributes(attrs string) string {
-	var extraAttrs string
+	options := parseOptionsArray(attrs)
+	if options == nil {
+		return ""
+	}
+	var extraAttrs strings.Builder
 	for _, attr := range ap.Order {
-		if pattern, exists := ap.Patterns[attr]; exists {
-			if re := regexCache.GetRegex(pattern); re.MatchString(attrs) {
-				matches := re.FindStringSubmatch(attrs)
-				var val string
-				if len(matches) > 2 && matches[2] != "" {
-					val = matches[2]
-				} else {
-					val = matches[1]
-				}
-				val = processAttributeValue(val)
-				if (attr == "disabled" && (val == "" || val == "disabled")) ||
-					(attr == "required" && (val == "" || val == "required")) {
-					extraAttrs += fmt.Sprintf(` %s`, attr)
-				} else {
-					extraAttrs += fmt.Sprintf(` %s="%s"`, attr, val)
-				}
+		if ap.skips(attr) {
+			continue
+		}
+		if value := arrayOption(options, attr); value != nil {
+			extraAttrs.WriteString(ap.attribute(attr, value))
+		} else if hasFlagOption(options, attr) {
+			extraAttrs.WriteString(" " + attr)
+		}
+	}
+	for _, item := range options.Items {
+		key, ok := item.Key.stringLiteral()
+		if !ok {
+			if key, ok = flagOption(item); ok && isAttributeName(key) && !ap.skips(key) && !ap.ordered(key) {
+				extraAttrs.WriteString(" " + key)
 			}
+			continue
 		}
+		if !isAttributeName(key) || ap.skips(key) || ap.ordered(key) {
+			continue
+		}
+		extraAttrs.WriteString(ap.attribute(key, item.Value))
 	}
-	return extraAttrs
+	return extraAttrs.String()
 }
 
-// Bladeパターン適用
-func ProcessBladePatterns(text, formMethod string, processor func(string) string) string {
-	patterns := []string{
-		fmt.Sprintf(BladeExclamationPattern, formMethod),
-		fmt.Sprintf(BladeCurlyPattern, formMethod),
+// attribute は1つの属性を「 key="value"」の形で返す。値が受け付けられない場合は空文字を返す。
+// true は値なしの属性、false / null は出力なしとし、真偽値属性の値が式なら @if で囲む。
+func (ap *AttributeProcessor) attribute(key string, value *phpNode) string {
+	switch {
+	case value == nil || value.isConst("false") || value.isConst("null"):
+		return ""
+	case value.isConst("true"):
+		return " " + key
 	}
-	for _, pattern := range patterns {
-		re := regexCache.GetRegex(pattern)
-		text = re.ReplaceAllStringFunc(text, func(match string) string {
-			matches := re.FindStringSubmatch(match)
-			if len(matches) > 1 {
-				return processor(matches[1])
-			}
-			return processor("")
-		})
-	}
-	return text
-}
-
-// 属性値処理
-func processAttributeValue(value string) string {
-	originalValue := value
-	value = strings.Trim(value, `'"`)
-	if strings.Contains(value, " . ") {
-		concatPattern1 := `^'([^']*)'\s*\.\s*(.+?)\s*\.\s*'([^']*)'$`
-		re1 := regexCache.GetRegex(concatPattern1)
-		if matches := re1.FindStringSubmatch(value); len(matches) == 4 {
-			prefix := matches[1]
-			variable := strings.TrimSpace(matches[2])
-			suffix := matches[3]
-			return fmt.Sprintf("{{ '%s' . %s . '%s' }}", prefix, variable, suffix)
+	if booleanAttributes[strings.ToLower(key)] {
+		if text, ok := value.bladeText(); ok && (text == "" || strings.EqualFold(text, key)) {
+			return " " + key
+		}
+		if value.Kind != phpString && value.Kind != phpNumber {
+			return fmt.Sprintf(" @if(%s) %s @endif", value.Src, key)
+		}
+	}
+	if isEventHandlerAttribute(key) && value.Kind == phpString && !value.Interp {
+		// JavaScript の "..." は属性値の中で読みやすいよう '...' にする（残りの " は &quot; になる）
+		converted := *value
+		converted.Value = convertJavaScriptStringLiterals(value.Value)
+		value = &converted
+	}
+	kind, exists := ap.Values[key]
+	if !exists {
+		kind = attrLiteral
+	}
+	val, ok := attributeValue(value, kind)
+	if !ok {
+		return ""
+	}
+	return fmt.Sprintf(` %s="%s"`, key, val)
+}
+
+// isEventHandlerAttribute は key が onclick 等のイベントハンドラー属性かを返す。
+func isEventHandlerAttribute(key string) bool {
+	return len(key) > 2 && strings.EqualFold(key[:2], "on")
+}
+
+// flagOption はキーのない（または整数キーの）要素の値が文字列リテラルなら、その値を返す。
+func flagOption(item phpArrayItem) (string, bool) {
+	if item.Spread || item.Key != nil && item.Key.Kind != phpNumber {
+		return "", false
+	}
+	return item.Value.stringLiteral()
+}
+
+// hasFlagOption はオプション配列に ['key'] の形の要素があるかを返す（大文字・小文字は区別しない）。
+func hasFlagOption(options *phpNode, key string) bool {
+	for _, item := range options.Items {
+		if flag, ok := flagOption(item); ok && strings.EqualFold(flag, key) {
+			return true
 		}
-		concatPattern2 := `^'([^']*)'\s*\.\s*(.+)$`
-		re2 := regexCache.GetRegex(concatPattern2)
-		if matches := re2.FindStringSubmatch(value); len(matches) == 3 {
-			prefix := matches[1]
-			variable := strings.TrimSpace(matches[2])
-			return fmt.Sprintf("{{ '%s' . %s }}", prefix, variable)
+	}
+	return false
+}
+
+// ordered は key が Order で出力済みのキーかを返す（大文字・小文字は区別しない）。
+func (ap *AttributeProcessor) ordered(key string) bool {
+	for _, attr := range ap.Order {
+		if strings.EqualFold(attr, key) {
+			return true
 		}
-		concatPattern3 := `^'([^']*)'\s*\.\s*(\$[a-zA-Z_]\w*(?:\[[^\]]*\])*)\s*$`
-		re3 := regexCache.GetRegex(concatPattern3)
-		if matches := re3.FindStringSubmatch(value); len(matches) == 3 {
-			prefix := matches[1]
-			variable := strings.TrimSpace(matches[2])
-			return fmt.Sprintf("{{ '%s' . %s }}", prefix, variable)
+	}
+	return false
+}
+
+// skips は key が Skip に当てはまるかを返す。
+func (ap *AttributeProcessor) skips(key string) bool {
+	for _, skip := range ap.Skip {
+		if strings.HasSuffix(skip, "-") && len(key) > len(skip) && strings.EqualFold(key[:len(skip)], skip) ||
+			strings.EqualFold(key, skip) {
+			return true
 		}
-		return fmt.Sprintf("{{ %s }}", originalValue)
 	}
-	return value
+	return false
 }
 
-// 値の整形
-func DetectArrayHelper(value string) bool {
-	return regexCache.GetRegex(`(?i)^(old|session|request|input)\s*\(`).MatchString(strings.TrimSpace(value))
+// isAttributeName は key が HTML の属性名として出力できるかを返す。
+func isAttributeName(key string) bool {
+	return key != "" && !strings.ContainsAny(key, " \t\r\n\"'<>/=`{}")
 }
 
+// parseOptionsArray はオプション配列のソースを解析する。括弧の内側だけが渡された場合は [] で囲んで解析する。
+// 配列として解析できない場合は nil を返す。
+func parseOptionsArray(src string) *phpNode {
+	src = strings.TrimSpace(src)
+	if node, err := parsePHPExpr(src); err == nil && node.Kind == phpArray {
+		return node
+	}
+	if node, err := parsePHPExpr("[" + src + "]"); err == nil {
+		return node
+	}
+	return nil
+}
+
+// arrayOption はオプション配列からキー key の値を返す。
+// 完全一致するキーがなければ大文字・小文字を区別せずに探す（onChange と onchange 等）。
+func arrayOption(options *phpNode, key string) *phpNode {
+	if value := options.arrayValue(key); value != nil || options == nil {
+		return value
+	}
+	for _, item := range options.Items {
+		if k, ok := item.Key.stringLiteral(); ok && strings.EqualFold(k, key) {
+			return item.Value
+		}
+	}
+	return nil
+}
+
+// attributeValue は値のノードが kind に当てはまれば、属性値として出力する文字列を返す。
+// リテラルでない式は {{ }} で囲んで返す。
+func attributeValue(node *phpNode, kind attrValue) (string, bool) {
+	if node == nil {
+		return "", false
+	}
+	if !isLiteral(node) {
+		return fmt.Sprintf("{{ %s }}", node.Src), true
+	}
+	text, isText := node.bladeText()
+	if !isText {
+		text, isText = node.Value, node.Kind == phpNumber
+	}
+	switch kind {
+	case attrText:
+		return text, isText && text != ""
+	case attrLiteral:
+		return text, isText
+	}
+	return "", false
+}
+
+// isLiteral は node が文字列・数値のリテラル、または true / false / null かを返す。
+func isLiteral(node *phpNode) bool {
+	return node.Kind == phpString || node.Kind == phpNumber ||
+		node.isConst("true") || node.isConst("false") || node.isConst("null")
+}
+
+// isIntegerLiteral は node が10進の整数リテラルかを返す。
+func isIntegerLiteral(node *phpNode) bool {
+	return node.Kind == phpNumber && strings.Trim(node.Value, "0123456789") == ""
+}
+
+// bladeContent は引数の記述を属性値・要素の内容として出力するテキストにする。
+// 文字列・数値のリテラルは静的なテキスト（変数展開の部分は {{ }}）、null は空文字、
+// それ以外の式は value_format の書式で出力する。
+func bladeContent(src string) string {
+	node, err := parsePHPExpr(src)
+	switch {
+	case err != nil:
+		return formatBladeValue(strings.TrimSpace(src))
+	case node.isConst("null"):
+		return ""
+	case node.Kind == phpNumber:
+		return node.Value
+	}
+	if text, ok := node.bladeText(); ok {
+		return text
+	}
+	return formatBladeValue(node.Src)
+}
+
+// conditionSource は checked / selected 等の引数を @if の条件にする記述を返す。
+// 省略（空文字）・null・false の場合は条件なし（空文字）を返す。
+func conditionSource(src string) string {
+	node, err := parsePHPExpr(src)
+	if err != nil || node.isConst("null") || node.isConst("false") {
+		return ""
+	}
+	return node.Src
+}
+
+// conditionalAttribute は checked 等の論理属性を、condition（conditionSource の結果）のときだけ出力する記述を返す。
+// リテラルの true なら属性をそのまま、その他の式は @if で囲む（条件なしなら空文字）。
+func conditionalAttribute(attr, condition string) string {
+	switch {
+	case condition == "":
+		return ""
+	case isTrueLiteral(condition):
+		return " " + attr
+	}
+	return fmt.Sprintf(" @if(%s) %s @endif", condition, attr)
+}
+
+// isTrueLiteral は src がリテラルの true かを返す。
+func isTrueLiteral(src string) bool {
+	node, err := parsePHPExpr(src)
+	return err == nil && node.isConst("true")
+}
+
+// 値の整形
 func IsArrayFieldName(fieldName string) bool {
 	return regexCache.GetRegex(`\[.*\]`).MatchString(fieldName)
 }
 
+// ProcessFieldName はフィールド名の式を name 属性の値にする。
+// 文字列連結のうちリテラル以外の部分と変数展開は {{ }} で出力する
+// （'item[' . $id . ']' / "item[{$id}]" → item[{{ $id }}]）。
 func ProcessFieldName(name string) string {
-	nameAttr := strings.Trim(name, `'"`)
-	if strings.Contains(nameAttr, " . ") {
-		concatPattern1 := `^'([^']*)'\s*\.\s*(.+?)\s*\.\s*'([^']*)'$`
-		re1 := regexCache.GetRegex(concatPattern1)
-		if matches := re1.FindStringSubmatch(nameAttr); len(matches) == 4 {
-			prefix := matches[1]
-			variable := strings.TrimSpace(matches[2])
-			suffix := matches[3]
-			return fmt.Sprintf("%s{{ %s }}%s", prefix, variable, suffix)
-		}
-		concatPattern2 := `^([^']*\[)'\s*\.\s*(.+?)\s*\.\s*'(\][^']*)$`
-		re2 := regexCache.GetRegex(concatPattern2)
-		if matches := re2.FindStringSubmatch(nameAttr); len(matches) == 4 {
-			prefix := matches[1]
-			variable := strings.TrimSpace(matches[2])
-			suffix := matches[3]
-			return fmt.Sprintf("%s{{ %s }}%s", prefix, variable, suffix)
+	node, err := parsePHPExpr(name)
+	if err != nil {
+		return strings.Trim(name, `'"`)
+	}
+	var b strings.Builder
+	for _, operand := range node.concatOperands() {
+		if text, ok := operand.bladeText(); ok {
+			b.WriteString(text)
+		} else {
+			fmt.Fprintf(&b, "{{ %s }}", operand.Src)
 		}
 	}
-	return nameAttr
+	return b.String()
 }
 
 func FormatValueAttribute(value string) string {
@@ -127,13 +288,13 @@ func FormatValueAttribute(value string) string {
 	if strings.HasPrefix(trimmedValue, "'") && strings.HasSuffix(trimmedValue, "'") {
 		innerValue := strings.Trim(trimmedValue, "'")
 		if regexCache.GetRegex(`^\d+(\.\d+)?$`).MatchString(innerValue) {
-			return fmt.Sprintf("{{ %s }}", innerValue)
+			return formatBladeValue(innerValue)
 		}
 		if regexCache.GetRegex(`^#[0-9a-fA-F]{3,6}$`).MatchString(innerValue) {
-			return fmt.Sprintf("{{ %s }}", innerValue)
+			return formatBladeValue(innerValue)
 		}
 	}
-	return fmt.Sprintf("{{ %s }}", value)
+	return formatBladeValue(value)
 }
 
 func FormatHiddenValueAttribute(value string, fieldName string) string {
@@ -142,7 +303,26 @@ func FormatHiddenValueAttribute(value string, fieldName string) string {
 		return ""
 	}
 	if IsArrayFieldName(fieldName) {
-		return fmt.Sprintf("{{ is_array(%s) ? implode(',', %s) : %s }}", value, value, value)
+		return formatBladeValue(fmt.Sprintf("is_array(%s) ? implode(',', %s) : %s", value, value, value))
+	}
+	return formatBladeValue(value)
+}
+
+// processDataAttributes はオプション配列の data-* 属性（文字列リテラルの値）を記述順に出力する。
+func processDataAttributes(attrs string) string {
+	options := parseOptionsArray(attrs)
+	if options == nil {
+		return ""
+	}
+	var result strings.Builder
+	for _, item := range options.Items {
+		key, ok := item.Key.stringLiteral()
+		if !ok || !strings.HasPrefix(key, "data-") {
+			continue
+		}
+		if value, ok := attributeValue(item.Value, attrText); ok {
+			fmt.Fprintf(&result, ` %s="%s"`, key, value)
+		}
 	}
-	return fmt.Sprintf("{{ %s }}", value)
+	return result.String()
 }
@@ -0,0 +1,297 @@
+// blade_lexer.go: Blade テンプレートを HTML・エコー・コメント・ディレクティブ等の領域に分割する字句解析。
+package ffr
+
+import "strings"
+
+// bladeRegionKind は Blade テンプレート内の領域の種類。
+type bladeRegionKind int
+
+const (
+	regionHTML        bladeRegionKind = iota // 通常の HTML（テキスト）
+	regionEcho                               // {{ ... }}
+	regionRawEcho                            // {!! ... !!}
+	regionEscapedEcho                        // @{{ ... }} / @{!! ... !!}（Vue 等のためにそのまま出力される）
+	regionComment                            // {{-- ... --}}
+	regionDirective                          // @if(...) 等のディレクティブ
+	regionPHP                                // @php ... @endphp / <?php ... ?>
+	regionPHPEcho                            // <?= 式 ?> / <?php echo 式; ?> / @php echo 式; @endphp（単一の echo 文）
+	regionVerbatim                           // @verbatim ... @endverbatim
+)
+
+// bladeRegion はテンプレート内の1つの領域（text[start:end]）。
+type bladeRegion struct {
+	kind     bladeRegionKind
+	start    int
+	end      int
+	inScript bool // <script> ～ </script> の内側にあるか
+}
+
+// isCode は領域の内容が PHP として実行されるかを返す。
+// コメント・エスケープされたエコー・@verbatim の中の Form:: は実際には呼び出されない。
+func (r bladeRegion) isCode() bool {
+	switch r.kind {
+	case regionEcho, regionRawEcho, regionDirective, regionPHP, regionPHPEcho:
+		return true
+	}
+	return false
+}
+
+// echoDelimiters はエコー領域の式を Blade で出力するときの開始・終了タグを返す。
+// PHP の echo は {!! !!} と同じくエスケープせずに出力する。
+func (r bladeRegion) echoDelimiters() (string, string) {
+	switch r.kind {
+	case regionEcho:
+		return "{{", "}}"
+	case regionRawEcho, regionPHPEcho:
+		return "{!!", "!!}"
+	}
+	return "", ""
+}
+
+// echoBody はエコー領域の式の範囲 text[start:end] を返す。
+func (r bladeRegion) echoBody(text string) (int, int, bool) {
+	switch r.kind {
+	case regionEcho, regionRawEcho:
+		opener, closer := echoTags(text[r.start:])
+		return r.start + len(opener), r.end - len(closer), true
+	case regionPHPEcho:
+		start, end, ok := phpEchoBody(text[r.start:r.end])
+		return r.start + start, r.start + end, ok
+	}
+	return 0, 0, false
+}
+
+// skipReason は領域内の Form:: を変換しない理由を返す（変換対象の領域なら空文字）。
+func (r bladeRegion) skipReason() string {
+	switch r.kind {
+	case regionComment:
+		return "Blade コメント内のため変換しません"
+	case regionEscapedEcho:
+		return "@ でエスケープされたエコー内のため変換しません"
+	case regionVerbatim:
+		return "@verbatim ブロック内のため変換しません"
+	case regionDirective:
+		return "ディレクティブの引数内のため変換しません"
+	case regionPHP:
+		return "PHP ブロック内のため変換しません"
+	case regionEcho, regionRawEcho, regionPHPEcho:
+		if r.inScript {
+			return "<script> ブロック内のため変換しません"
+		}
+	}
+	return ""
+}
+
+// lexBlade はテンプレート全体を先頭から順に領域へ分割する（領域は隙間なく連続する）。
+func lexBlade(text string) []bladeRegion {
+	var regions []bladeRegion
+	htmlStart := 0
+	scriptEnd := -1 // 現在の <script> ブロックの終わり（ブロック外なら -1）
+
+	emit := func(kind bladeRegionKind, start, end int) {
+		inScript := start < scriptEnd
+		if htmlStart < start {
+			regions = append(regions, bladeRegion{regionHTML, htmlStart, start, htmlStart < scriptEnd})
+		}
+		regions = append(regions, bladeRegion{kind, start, end, inScript})
+		htmlStart = end
+	}
+
+	for i := 0; i < len(text); {
+		if scriptEnd >= 0 && i >= scriptEnd {
+			scriptEnd = -1
+		}
+		rest := text[i:]
+		switch {
+		case strings.HasPrefix(rest, "{{--"):
+			end := indexFrom(text, "--}}", i+len("{{--"))
+			emit(regionComment, i, end)
+		case strings.HasPrefix(rest, "@{{") || strings.HasPrefix(rest, "@{!!"):
+			opener, closer := echoTags(rest[1:])
+			end, ok := echoEnd(text, i+1+len(opener), closer)
+			if !ok {
+				i += 1 + len(opener)
+				continue
+			}
+			emit(regionEscapedEcho, i, end)
+		case strings.HasPrefix(rest, "{{") || strings.HasPrefix(rest, "{!!"):
+			opener, closer := echoTags(rest)
+			end, ok := echoEnd(text, i+len(opener), closer)
+			if !ok {
+				// 閉じていないエコーは Blade でもそのまま出力される
+				i += len(opener)
+				continue
+			}
+			kind := regionEcho
+			if opener == "{!!" {
+				kind = regionRawEcho
+			}
+			emit(kind, i, end)
+		case strings.HasPrefix(rest, "<?php") || strings.HasPrefix(rest, "<?="):
+			end := indexFrom(text, "?>", i+len("<?"))
+			emit(phpRegionKind(text[i:end]), i, end)
+		case strings.HasPrefix(rest, "@@"):
+			// @@if は @if という文字列をそのまま出力する
+			i += len("@@") + len(leadingIdentifier(text[i+len("@@"):]))
+			continue
+		case rest[0] == '@' && (i == 0 || !isWordByte(text[i-1])) && leadingIdentifier(rest[1:]) != "":
+			emit(directiveRegion(text, i))
+		case hasPrefixFold(rest, "<script") && (len(rest) == len("<script") || !isWordByte(rest[len("<script")])):
+			if close := indexFold(text[i:], "</script"); close >= 0 {
+				scriptEnd = i + close
+			} else {
+				scriptEnd = len(text)
+			}
+			i += len("<script")
+			continue
+		default:
+			i++
+			continue
+		}
+		i = htmlStart
+	}
+	if htmlStart < len(text) {
+		regions = append(regions, bladeRegion{regionHTML, htmlStart, len(text), htmlStart < scriptEnd})
+	}
+	return regions
+}
+
+// directiveRegion は start の "@" から始まるディレクティブの種類と範囲を返す。
+// @php / @verbatim は対応する終了ディレクティブまでを1つの領域とする。
+func directiveRegion(text string, start int) (bladeRegionKind, int, int) {
+	name := leadingIdentifier(text[start+1:])
+	end := start + 1 + len(name)
+	args := end
+	for args < len(text) && (text[args] == ' ' || text[args] == '\t') {
+		args++
+	}
+	hasArgs := args < len(text) && text[args] == '('
+
+	switch {
+	case name == "verbatim":
+		return regionVerbatim, start, indexFrom(text, "@endverbatim", end)
+	case name == "php" && !hasArgs:
+		close := indexFrom(text, "@endphp", end)
+		return phpRegionKind(text[start:close]), start, close
+	case hasArgs:
+		if close, ok := matchingParen(text, args); ok {
+			return regionDirective, start, close + 1
+		}
+	}
+	return regionDirective, start, end
+}
+
+// phpRegionKind は PHP ブロックが単一の echo 文なら regionPHPEcho を、それ以外は regionPHP を返す。
+func phpRegionKind(block string) bladeRegionKind {
+	if _, _, ok := phpEchoBody(block); ok {
+		return regionPHPEcho
+	}
+	return regionPHP
+}
+
+// phpEchoBody は PHP ブロック block が単一の echo 文
+// （<?= 式 ?> / <?php echo 式; ?> / @php echo 式; @endphp）なら、式の範囲 block[start:end] を返す。
+func phpEchoBody(block string) (int, int, bool) {
+	start, closer, keyword := 0, "?>", true
+	switch {
+	case strings.HasPrefix(block, "<?="):
+		start, keyword = len("<?="), false
+	case strings.HasPrefix(block, "<?php"):
+		start = len("<?php")
+	case strings.HasPrefix(block, "@php"):
+		start, closer = len("@php"), "@endphp"
+	default:
+		return 0, 0, false
+	}
+	if !strings.HasSuffix(block, closer) || len(block)-len(closer) < start {
+		return 0, 0, false
+	}
+	end := len(block) - len(closer)
+	if keyword {
+		i := start
+		for i < end && isBladeSpace(block[i]) {
+			i++
+		}
+		if i == start || !hasPrefixFold(block[i:end], "echo") || i+len("echo") < end && isWordByte(block[i+len("echo")]) {
+			return 0, 0, false
+		}
+		start = i + len("echo")
+	}
+	body := strings.TrimRight(block[start:end], " \t\r\n")
+	end = start + len(strings.TrimSuffix(body, ";"))
+	// 式の中に ; があれば複数の文からなるブロック
+	tokens, err := lexPHP(block[start:end])
+	if err != nil || len(tokens) == 1 {
+		return 0, 0, false
+	}
+	for _, tok := range tokens {
+		if tok.kind == phpTokenOp && tok.text == ";" {
+			return 0, 0, false
+		}
+	}
+	return start, end, true
+}
+
+// echoTags は s の先頭にあるエコーの開始タグと、対応する終了タグを返す。
+func echoTags(s string) (string, string) {
+	if strings.HasPrefix(s, "{!!") {
+		return "{!!", "!!}"
+	}
+	return "{{", "}}"
+}
+
+// echoEnd は from 以降で closer が現れる位置の直後を返す。
+// 文字列リテラル内の closer は無視するが、文字列が閉じていない場合は最初の closer で閉じる。
+func echoEnd(text string, from int, closer string) (int, bool) {
+	var quote byte
+	for i := from; i < len(text); i++ {
+		c := text[i]
+		if quote != 0 {
+			if c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+			continue
+		}
+		if c == '\'' || c == '"' {
+			quote = c
+			continue
+		}
+		if strings.HasPrefix(text[i:], closer) {
+			return i + len(closer), true
+		}
+	}
+	if idx := strings.Index(text[from:], closer); idx >= 0 {
+		return from + idx + len(closer), true
+	}
+	return 0, false
+}
+
+// indexFrom は from 以降で s が現れる位置の直後を返す（見つからなければテキストの終わり）。
+func indexFrom(text, s string, from int) int {
+	if idx := strings.Index(text[from:], s); idx >= 0 {
+		return from + idx + len(s)
+	}
+	return len(text)
+}
+
+// indexFold は大文字・小文字を区別せずに s が現れる位置を返す（見つからなければ -1）。
+func indexFold(text, s string) int {
+	for i := 0; i+len(s) <= len(text); i++ {
+		if hasPrefixFold(text[i:], s) {
+			return i
+		}
+	}
+	return -1
+}
+
+// hasPrefixFold は大文字・小文字を区別せずに text が prefix で始まるかを返す。
+func hasPrefixFold(text, prefix string) bool {
+	return len(text) >= len(prefix) && strings.EqualFold(text[:len(prefix)], prefix)
+}
+
+// isWordByte は識別子・メールアドレス等の一部になる文字かを返す。
+func isWordByte(c byte) bool {
+	return c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c >= 0x80
+}
@@ -0,0 +1,149 @@
+package ffr
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestLexBlade(t *testing.T) {
+	type region struct {
+		kind     bladeRegionKind
+		text     string
+		inScript bool
+	}
+	tests := []struct {
+		name     string
+		input    string
+		expected []region
+	}{
+		{
+			name:  "Echoes and comments",
+			input: "<p>{{ $a }}</p>{!! $b !!}{{-- {{ $c }} --}}",
+			expected: []region{
+				{regionHTML, "<p>", false},
+				{regionEcho, "{{ $a }}", false},
+				{regionHTML, "</p>", false},
+				{regionRawEcho, "{!! $b !!}", false},
+				{regionComment, "{{-- {{ $c }} --}}", false},
+			},
+		},
+		{
+			name:  "Escaped echoes and directives",
+			input: "@{{ name }} @if($a == '{{') x @endif @@if mail@example.com",
+			expected: []region{
+				{regionEscapedEcho, "@{{ name }}", false},
+				{regionHTML, " ", false},
+				{regionDirective, "@if($a == '{{')", false},
+				{regionHTML, " x ", false},
+				{regionDirective, "@endif", false},
+				{regionHTML, " @@if mail@example.com", false},
+			},
+		},
+		{
+			name:  "Verbatim and PHP blocks",
+			input: "@verbatim {{ a }} @endverbatim\n@php echo 1; @endphp\n@php($x = 1)<?php echo 2; ?>\n<?php $a = 1; echo $a; ?><?= $b ?>",
+			expected: []region{
+				{regionVerbatim, "@verbatim {{ a }} @endverbatim", false},
+				{regionHTML, "\n", false},
+				{regionPHPEcho, "@php echo 1; @endphp", false},
+				{regionHTML, "\n", false},
+				{regionDirective, "@php($x = 1)", false},
+				{regionPHPEcho, "<?php echo 2; ?>", false},
+				{regionHTML, "\n", false},
+				{regionPHP, "<?php $a = 1; echo $a; ?>", false},
+				{regionPHPEcho, "<?= $b ?>", false},
+			},
+		},
+		{
+			name:  "Script blocks",
+			input: "<SCRIPT>var a = `{{ $a }}`;</SCRIPT>{{ $b }}",
+			expected: []region{
+				{regionHTML, "<SCRIPT>var a = `", true},
+				{regionEcho, "{{ $a }}", true},
+				{regionHTML, "`;</SCRIPT>", false},
+				{regionEcho, "{{ $b }}", false},
+			},
+		},
+		{
+			name:  "Closing tag inside a string and unterminated echo",
+			input: "{{ ')}}' }} {{ $a",
+			expected: []region{
+				{regionEcho, "{{ ')}}' }}", false},
+				{regionHTML, " {{ $a", false},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []region
+			for _, r := range lexBlade(tt.input) {
+				got = append(got, region{r.kind, tt.input[r.start:r.end], r.inScript})
+			}
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("lexBlade() =\n%+v\nwant:\n%+v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestConvertTemplateSkipsNonEchoRegions(t *testing.T) {
+	input := `{{-- {{ Form::text('a') }} --}}
+<div id="app">@{{ Form::text('b') }}</div>
+@verbatim
+    {{ Form::text('c') }}
+@endverbatim
+<script>const tpl = ` + "`{!! Form::text('d') !!}`" + `;</script>
+@if(Form::old('e'))
+{{ Form::text('f') }}
+@endif`
+	expected := `{{-- {{ Form::text('a') }} --}}
+<div id="app">@{{ Form::text('b') }}</div>
+@verbatim
+    {{ Form::text('c') }}
+@endverbatim
+<script>const tpl = ` + "`{!! Form::text('d') !!}`" + `;</script>
+@if(Form::old('e'))
+<input type="text" name="f" value="">
+@endif`
+
+	result, warnings := convertTemplate(input)
+	if result != expected {
+		t.Errorf("convertTemplate() =\n%s\nwant:\n%s", result, expected)
+	}
+	expectedWarnings := []conversionWarning{
+		{Line: 1, Column: 9, Method: "text", Message: "Blade コメント内のため変換しません", Skipped: true},
+		{Line: 2, Column: 19, Method: "text", Message: "@ でエスケープされたエコー内のため変換しません", Skipped: true},
+		{Line: 4, Column: 8, Method: "text", Message: "@verbatim ブロック内のため変換しません", Skipped: true},
+		{Line: 6, Column: 26, Method: "text", Message: "<script> ブロック内のため変換しません", Skipped: true},
+		{Line: 7, Column: 5, Method: "old", Message: "ディレクティブの引数内のため変換しません", Skipped: true},
+	}
+	if !reflect.DeepEqual(warnings, expectedWarnings) {
+		t.Errorf("warnings =\n%+v\nwant:\n%+v", warnings, expectedWarnings)
+	}
+
+	// 実行されない領域の Form:: は残存箇所として数えない
+	var remaining []int
+	for _, occ := range findFormFacadeOccurrences(result) {
+		remaining = append(remaining, occ.Line)
+	}
+	if !reflect.DeepEqual(remaining, []int{6, 7}) {
+		t.Errorf("remaining lines = %v, want [6 7]", remaining)
+	}
+}
+
+func TestReportSeparatesSkippedOccurrences(t *testing.T) {
+	original := "{{-- {{ Form::text('a') }} --}}\n{{ Form::text('b') $x }}\n"
+	converted, warnings := convertTemplate(original)
+	report := buildFileReport(&FileResult{Path: "a.blade.php", Original: original, Converted: converted, Warnings: warnings})
+
+	if expected := []reportMessage{{Line: 1, Column: 9, Method: "text", Message: "Blade コメント内のため変換しません"}}; !reflect.DeepEqual(report.Skipped, expected) {
+		t.Errorf("Skipped = %v, want %v", report.Skipped, expected)
+	}
+	if expected := []reportMessage{{Line: 2, Column: 4, Method: "text", Message: "呼び出しの直後でエコーが閉じていないため変換できません"}}; !reflect.DeepEqual(report.Warnings, expected) {
+		t.Errorf("Warnings = %v, want %v", report.Warnings, expected)
+	}
+	if len(report.Remaining) != 1 || report.Remaining[0].Line != 2 {
+		t.Errorf("Remaining = %+v, want only line 2", report.Remaining)
+	}
+}
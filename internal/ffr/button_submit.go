@@ -9,29 +9,25 @@ import (
 // --- Button ---
 // replaceFormButton は Blade 内の Form::button(...) を HTML に置換する。
 func replaceFormButton(text string) string {
-	singleParamPatterns := []string{
-		`(?s)\{\{\s*Form::button\(\s*'([^']*)'\s*\)\s*\}\}`,
-		`(?s)\{\!\!\s*Form::button\(\s*'([^']*)'\s*\)\s*\!\!\}`,
-	}
-	for _, pattern := range singleParamPatterns {
-		re := regexCache.GetRegex(pattern)
-		text = re.ReplaceAllStringFunc(text, func(match string) string {
-			matches := re.FindStringSubmatch(match)
-			return processFormButton(matches[1], "")
-		})
-	}
-	twoParamPatterns := []string{
-		`(?s)\{\!\!\s*Form::button\(\s*(.*?)\s*,\s*\[\s*(.*?)\s*\]\s*\)\s*\!\!\}`,
-		`(?s)\{\{\s*Form::button\(\s*(.*?)\s*,\s*\[\s*(.*?)\s*\]\s*\)\s*\}\}`,
-	}
-	for _, pattern := range twoParamPatterns {
-		re := regexCache.GetRegex(pattern)
-		text = re.ReplaceAllStringFunc(text, func(match string) string {
-			matches := re.FindStringSubmatch(match)
-			return processFormButton(matches[1], matches[2])
-		})
+	return replaceFormMethod(text, "button")
+}
+
+// handleFormButton は Form::button('テキスト') と Form::button(テキスト, [...]) を処理する。
+func handleFormButton(args string) (string, bool) {
+	params, ok := positionalArgs(args)
+	switch {
+	case !ok:
+	case len(params) == 1 && isPlainSingleQuoted(params[0].Src):
+		return processFormButton(params[0].Src[1:len(params[0].Src)-1], ""), true
+	case len(params) == 2 && params[1].Kind == phpArray:
+		return processFormButton(params[0].Src, arrayContent(params[1])), true
 	}
-	return text
+	return "", false
+}
+
+// isPlainSingleQuoted は s が内部に ' を含まないシングルクォート文字列かを返す。
+func isPlainSingleQuoted(s string) bool {
+	return len(s) >= 2 && s[0] == '\'' && s[len(s)-1] == '\'' && !strings.Contains(s[1:len(s)-1], "'")
 }
 
 // processFormButton は button の属性（type/onclick/data- 等）を整形してHTMLを生成する。
@@ -40,20 +36,16 @@ func processFormButton(textParam, attrs string) string {
 		return fmt.Sprintf(`<button>{!! %s !!}</button>`, textParam)
 	}
 	attrProcessor := &AttributeProcessor{
-		Order: []string{"type", "onclick", "class", "id", "disabled"},
-		Patterns: map[string]string{
-			"type":     `'type'\s*=>\s*'([^']+)'`,
-			"onclick":  `'onclick'\s*=>\s*'([^']+)'`,
-			"class":    `'class'\s*=>\s*'([^']+)'`,
-			"id":       `'id'\s*=>\s*'([^']+)'`,
-			"disabled": `'disabled'\s*=>\s*'([^']+)'`,
+		Order: attributeOrder("button", []string{"type", "onclick", "class", "id", "disabled"}),
+		Values: map[string]attrValue{
+			"type":     attrText,
+			"onclick":  attrText,
+			"class":    attrText,
+			"id":       attrText,
+			"disabled": attrText,
 		},
 	}
 	extraAttrs := attrProcessor.ProcessAttributes(attrs)
-	dataRe := regexCache.GetRegex(`'(data-[^']+)'\s*=>\s*'([^']+)'`)
-	for _, match := range dataRe.FindAllStringSubmatch(attrs, -1) {
-		extraAttrs += fmt.Sprintf(` %s="%s"`, match[1], match[2])
-	}
 	extraAttrs += processDynamicAttributes(attrs)
 	return fmt.Sprintf(`<button%s>{!! %s !!}</button>`, extraAttrs, textParam)
 }
@@ -61,22 +53,7 @@ func processFormButton(textParam, attrs string) string {
 // --- Submit ---
 // replaceFormSubmit は Blade 内の Form::submit(...) を HTML に置換する。
 func replaceFormSubmit(text string) string {
-	patterns := []string{
-		`(?is)\{\!\!\s*Form::submit\(\s*(.*?)\s*\)\s*\!\!\}`,
-		`(?is)\{\{\s*Form::submit\(\s*(.*?)\s*\)\s*\}\}`,
-	}
-	for _, pattern := range patterns {
-		re := regexCache.GetRegex(pattern)
-		text = re.ReplaceAllStringFunc(text, func(match string) string {
-			fullMatch := re.FindStringSubmatch(match)
-			if len(fullMatch) > 1 {
-				params := extractParamsBalanced(fullMatch[1])
-				return processFormSubmit(params)
-			}
-			return match
-		})
-	}
-	return text
+	return replaceFormMethod(text, "submit")
 }
 
 // processFormSubmit は submit のテキストと属性を整形してHTMLを生成する。
@@ -84,19 +61,17 @@ func processFormSubmit(params []string) string {
 	if len(params) < 1 {
 		return ""
 	}
-	textParam := strings.Trim(params[0], `'"`)
-	if textParam == "null" {
-		textParam = ""
-	}
+	textParam := bladeContent(params[0])
 	attrProcessor := &AttributeProcessor{
-		Order: []string{"class", "id", "style", "onclick", "disabled"},
-		Patterns: map[string]string{
-			"class":    `'class'\s*=>\s*'([^']+)'`,
-			"id":       `'id'\s*=>\s*'([^']+)'`,
-			"style":    `'style'\s*=>\s*'([^']+)'`,
-			"onclick":  `'onclick'\s*=>\s*'([^']+)'`,
-			"disabled": `'disabled'\s*=>\s*'([^']*)'`,
+		Order: attributeOrder("submit", []string{"class", "id", "style", "onclick", "disabled"}),
+		Values: map[string]attrValue{
+			"class":    attrText,
+			"id":       attrText,
+			"style":    attrText,
+			"onclick":  attrText,
+			"disabled": attrLiteral,
 		},
+		Skip: []string{"type"},
 	}
 	extraAttrs := ""
 	if len(params) > 1 {
@@ -36,9 +36,3 @@ func (rc *RegexCache) GetRegex(pattern string) *regexp.Regexp {
 	rc.cache[pattern] = re
 	return re
 }
-
-// Bladeの基本パターン
-const (
-	BladeExclamationPattern = `\{\!\!\s*%s\s*\!\!\}`
-	BladeCurlyPattern       = `\{\{\s*%s\s*\}\}`
-)
@@ -0,0 +1,165 @@
+// check.go: CI 向けチェックモード（--check）の判定と結果表示。
+package ffr
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// --check モードの終了コード
+const (
+	exitCheckClean       = 0 // 変換が必要なファイルも残存 Form:: もない
+	exitCheckChanges     = 1 // 書き換えが必要なファイルがある
+	exitCheckUnconverted = 2 // 変換できない Form:: の使用箇所が残る
+	exitCheckError       = 3 // 処理中にエラーが発生した
+)
+
+// facadeOccurrence は Form:: の出現位置（1始まりの行・桁）を表す。
+type facadeOccurrence struct {
+	Line   int
+	Column int
+	Method string // Form:: に続くメソッド名（識別子でなければ空文字）
+	Text   string // 出現した行（前後の空白を除去）
+	offset int    // テキスト上の Form:: の位置
+}
+
+// findFormFacadeOccurrences はテキスト中の Form:: （\Form:: や設定した別名を含む）の出現位置をすべて返す。
+// Blade コメント・エスケープされたエコー・@verbatim の中は実行されないため除く。
+func findFormFacadeOccurrences(text string) []facadeOccurrence {
+	var occurrences []facadeOccurrence
+	inert := inertRanges(text)
+	lineStart := 0
+	for i, line := range strings.Split(text, "\n") {
+		for _, ref := range findFacadeRefs(line, 0, len(line)) {
+			if inert.contains(lineStart + ref.start) {
+				continue
+			}
+			occurrences = append(occurrences, facadeOccurrence{
+				Line:   i + 1,
+				Column: utf8.RuneCountInString(line[:ref.start]) + 1,
+				Method: leadingIdentifier(line[ref.end:]),
+				Text:   strings.TrimSpace(line),
+				offset: lineStart + ref.start,
+			})
+		}
+		lineStart += len(line) + 1
+	}
+	return occurrences
+}
+
+// remainingFacades は変換後も残る Form:: の出現位置を、変換前のファイル上の行・桁で返す。
+// 変換で行数が変わっても、元のファイルで該当箇所を探せるようにするため。
+func (r *FileResult) remainingFacades() []facadeOccurrence {
+	occurrences := findFormFacadeOccurrences(r.Converted)
+	if r.Sources == nil {
+		return occurrences
+	}
+	for i, occ := range occurrences {
+		offset := r.Sources.originalOffset(r.Original, r.Converted, occ.offset)
+		occurrences[i].Line, occurrences[i].Column = offsetPosition(r.Original, offset)
+		occurrences[i].Text = strings.TrimSpace(lineAt(r.Original, offset))
+		occurrences[i].offset = offset
+	}
+	return occurrences
+}
+
+// lineAt は text の offset を含む行（改行を除く）を返す。
+func lineAt(text string, offset int) string {
+	start := strings.LastIndex(text[:offset], "\n") + 1
+	end := len(text)
+	if n := strings.IndexByte(text[offset:], '\n'); n >= 0 {
+		end = offset + n
+	}
+	return text[start:end]
+}
+
+// textRanges はテキスト上の範囲 [start, end) の並び。
+type textRanges [][2]int
+
+// inertRanges は Form:: が実行されない領域（コメント・エスケープされたエコー・@verbatim）の範囲を返す。
+func inertRanges(text string) textRanges {
+	if !containsFacadeRef(text) {
+		return nil
+	}
+	var ranges textRanges
+	for _, region := range lexBlade(text) {
+		if region.kind != regionHTML && !region.isCode() {
+			ranges = append(ranges, [2]int{region.start, region.end})
+		}
+	}
+	return ranges
+}
+
+// contains は offset がいずれかの範囲に含まれるかを返す。
+func (r textRanges) contains(offset int) bool {
+	for _, rg := range r {
+		if rg[0] <= offset && offset < rg[1] {
+			return true
+		}
+	}
+	return false
+}
+
+// leadingIdentifier は s の先頭にある PHP の識別子を返す。
+func leadingIdentifier(s string) string {
+	end := 0
+	for end < len(s) {
+		c := s[end]
+		if c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || end > 0 && c >= '0' && c <= '9' {
+			end++
+			continue
+		}
+		break
+	}
+	return s[:end]
+}
+
+// printCheckReport はチェックモードの結果を表示し、終了コードを返す。
+// 変換できない Form:: が残る場合は書き換え対象の有無より優先して exitCheckUnconverted を返す。
+func printCheckReport(config *ReplacementConfig) int {
+	var changed []*FileResult
+	unconverted := 0
+	for _, result := range config.Results {
+		if result.Changed() {
+			changed = append(changed, result)
+		}
+	}
+
+	if len(changed) > 0 {
+		fmt.Fprintln(config.messages(), "=== 書き換えが必要なファイル ===")
+		for _, result := range changed {
+			fmt.Fprintln(config.messages(), result.Path)
+		}
+		fmt.Fprintln(config.messages())
+	}
+
+	for _, result := range config.Results {
+		occurrences := result.remainingFacades()
+		if len(occurrences) == 0 {
+			continue
+		}
+		if unconverted == 0 {
+			fmt.Fprintln(config.messages(), "=== 変換できない Form:: の使用箇所 ===")
+		}
+		for _, occ := range occurrences {
+			fmt.Fprintf(config.messages(), "%s:%d:%d: %s\n", result.Path, occ.Line, occ.Column, occ.Text)
+		}
+		unconverted += len(occurrences)
+	}
+	if unconverted > 0 {
+		fmt.Fprintln(config.messages())
+	}
+
+	switch {
+	case unconverted > 0:
+		fmt.Fprintf(config.messages(), "チェック失敗: 変換できない Form:: が %d 箇所あります（書き換えが必要なファイル: %d）\n", unconverted, len(changed))
+		return exitCheckUnconverted
+	case len(changed) > 0:
+		fmt.Fprintf(config.messages(), "チェック失敗: %d ファイルに変換可能な Form:: が残っています\n", len(changed))
+		return exitCheckChanges
+	default:
+		fmt.Fprintln(config.messages(), "チェック成功: 変換が必要な Form:: はありません")
+		return exitCheckClean
+	}
+}
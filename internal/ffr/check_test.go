@@ -0,0 +1,113 @@
+package ffr
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestFindFormFacadeOccurrences(t *testing.T) {
+	text := "<div>\n  {{ Form::foo('a') }} {{ Form::bar('b') }}\n</div>\n<p>ä {{ Form::baz() }}</p>"
+	expected := []facadeOccurrence{
+		{Line: 2, Column: 6, Method: "foo", Text: "{{ Form::foo('a') }} {{ Form::bar('b') }}", offset: 11},
+		{Line: 2, Column: 27, Method: "bar", Text: "{{ Form::foo('a') }} {{ Form::bar('b') }}", offset: 32},
+		{Line: 4, Column: 9, Method: "baz", Text: "<p>ä {{ Form::baz() }}</p>", offset: 66},
+	}
+
+	result := findFormFacadeOccurrences(text)
+	if len(result) != len(expected) {
+		t.Fatalf("findFormFacadeOccurrences() returned %d occurrences, want %d: %+v", len(result), len(expected), result)
+	}
+	for i := range expected {
+		if result[i] != expected[i] {
+			t.Errorf("occurrence[%d] = %+v, want %+v", i, result[i], expected[i])
+		}
+	}
+}
+
+func TestCheckModeExitCodes(t *testing.T) {
+	tests := []struct {
+		name     string
+		files    map[string]string
+		expected int
+	}{
+		{
+			name: "Nothing to convert",
+			files: map[string]string{
+				"plain.blade.php": `<input type="text" name="name">`,
+			},
+			expected: exitCheckClean,
+		},
+		{
+			name: "Convertible Form calls remain",
+			files: map[string]string{
+				"form.blade.php":  `{{ Form::text('name') }}`,
+				"plain.blade.php": `<p>done</p>`,
+			},
+			expected: exitCheckChanges,
+		},
+		{
+			name: "Unconvertible Form calls remain",
+			files: map[string]string{
+				"form.blade.php":   `{{ Form::text('name') }}`,
+				"custom.blade.php": `{{ Form::customMacro('x') }}`,
+			},
+			expected: exitCheckUnconverted,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tempDir := t.TempDir()
+			for filename, content := range tt.files {
+				if err := os.WriteFile(filepath.Join(tempDir, filename), []byte(content), 0644); err != nil {
+					t.Fatalf("Failed to create test file %s: %v", filename, err)
+				}
+			}
+
+			code := Run([]string{"form-facade-replacer", "--check", tempDir})
+			if code != tt.expected {
+				t.Errorf("Run(--check) = %d, want %d", code, tt.expected)
+			}
+
+			// チェックモードではファイルを書き換えない
+			for filename, content := range tt.files {
+				result, err := os.ReadFile(filepath.Join(tempDir, filename))
+				if err != nil {
+					t.Fatalf("Failed to read %s: %v", filename, err)
+				}
+				if string(result) != content {
+					t.Errorf("check mode modified %s:\n%s", filename, string(result))
+				}
+			}
+		})
+	}
+}
+
+func TestCheckModeReportsOriginalPositions(t *testing.T) {
+	tempDir := t.TempDir()
+	path := filepath.Join(tempDir, "form.blade.php")
+	// 前の select が複数行に展開されても、元のファイルでの行・桁を表示する
+	content := "{{ Form::select('size', ['L' => 'Large', 'S' => 'Small']) }}\n<p>{{ Form::customMacro('x') }}</p>\n"
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	var stdout, stderr strings.Builder
+	code := RunWith([]string{"form-facade-replacer", "--check", tempDir}, strings.NewReader(""), &stdout, &stderr)
+	if code != exitCheckUnconverted {
+		t.Fatalf("RunWith(--check) = %d, want %d", code, exitCheckUnconverted)
+	}
+	expected := path + ":2:7: <p>{{ Form::customMacro('x') }}</p>\n"
+	if !strings.Contains(stdout.String()+stderr.String(), expected) {
+		t.Errorf("check output does not contain %q:\n%s", expected, stdout.String()+stderr.String())
+	}
+}
+
+func TestCheckModeMissingTarget(t *testing.T) {
+	code := Run([]string{"form-facade-replacer", "--check", filepath.Join(t.TempDir(), "missing")})
+	if code != exitCheckError {
+		t.Errorf("Run(--check) = %d, want %d", code, exitCheckError)
+	}
+}
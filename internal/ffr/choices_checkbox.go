@@ -9,22 +9,7 @@ import (
 // --- Checkbox ---
 // replaceFormCheckbox は Blade 内の Form::checkbox(...) を HTML に置換する。
 func replaceFormCheckbox(text string) string {
-	patterns := []string{
-		`(?s)\{\{\s*Form::checkbox\(\s*(.*?)\s*\)\s*\}\}`,
-		`(?s)\{\!\!\s*Form::checkbox\(\s*(.*?)\s*\)\s*\!\!\}`,
-	}
-	for _, pattern := range patterns {
-		re := regexCache.GetRegex(pattern)
-		text = re.ReplaceAllStringFunc(text, func(match string) string {
-			fullMatch := re.FindStringSubmatch(match)
-			if len(fullMatch) > 1 {
-				params := extractParamsBalanced(fullMatch[1])
-				return processFormCheckbox(params)
-			}
-			return match
-		})
-	}
-	return text
+	return replaceFormMethod(text, "checkbox")
 }
 
 // processFormCheckbox は Checkbox 要素の属性・checked を解決して最終HTMLを生成する。
@@ -33,39 +18,46 @@ func processFormCheckbox(params []string) string {
 		return ""
 	}
 	name := ProcessFieldName(params[0])
-	value := ""
+	// 値を省略した場合は Collective と同じく 1
+	value, valueAttr := "1", "1"
 	if len(params) > 1 {
-		value = strings.Trim(params[1], `'"`)
+		value, valueAttr = params[1], bladeContent(params[1])
 	}
 	checked := ""
 	if len(params) > 2 {
-		checked = params[2]
+		checked = conditionSource(params[2])
 	}
 	attrProcessor := &AttributeProcessor{
-		Order: []string{"class", "id", "style", "disabled", "onClick", "onChange"},
-		Patterns: map[string]string{
-			"class":    `'class'\s*=>\s*(.+?)(?:\s*,|\s*\]|$)`,
-			"id":       `'id'\s*=>\s*(.+?)(?:\s*,|\s*\]|$)`,
-			"style":    `'style'\s*=>\s*(.+?)(?:\s*,|\s*\]|$)`,
-			"disabled": `'disabled'\s*=>\s*(.+?)(?:\s*,|\s*\]|$)`,
-			"onClick":  `'onClick'\s*=>\s*'([^']+)'`,
-			"onChange": `'onChange'\s*=>\s*'([^']+)'`,
+		Order: attributeOrder("checkbox", []string{"class", "id", "style", "disabled", "onClick", "onChange"}),
+		Values: map[string]attrValue{
+			"class":    attrLiteral,
+			"id":       attrLiteral,
+			"style":    attrLiteral,
+			"disabled": attrLiteral,
+			"onClick":  attrText,
+			"onChange": attrText,
 		},
+		Skip: []string{"type", "name", "value", "checked", "data-"},
 	}
 	extraAttrs := ""
 	if len(params) > 3 {
-		dataRe := regexCache.GetRegex(`'(data-[^']+)'\s*=>\s*'([^']+)'`)
-		for _, match := range dataRe.FindAllStringSubmatch(params[3], -1) {
-			extraAttrs += fmt.Sprintf(` %s="%s"`, match[1], match[2])
-		}
+		extraAttrs += processDataAttributes(params[3])
 		extraAttrs += attrProcessor.ProcessAttributes(params[3])
 	}
-	var result string
-	if strings.HasSuffix(name, "[]") {
-		result = fmt.Sprintf(`<input type="checkbox" name="%s" value="{{ %s }}" @if(in_array(%s, (array)%s)) checked @endif%s>`, name, value, value, checked, extraAttrs)
-	} else {
-		result = fmt.Sprintf(`<input type="checkbox" name="%s" value="{{ %s }}" @if(%s) checked @endif%s>`, name, value, checked, extraAttrs)
+	checkedAttr := conditionalAttribute("checked", checked)
+	if strings.HasSuffix(name, "[]") && checked != "" && !isTrueLiteral(checked) {
+		checkedAttr = fmt.Sprintf(" @if(in_array(%s, (array)%s)) checked @endif", value, castOperand(checked))
 	}
+	result := fmt.Sprintf(`<input type="checkbox" name="%s" value="%s"%s%s>`, name, valueAttr, checkedAttr, extraAttrs)
 	result = convertEventHandlerQuotesInHTML(result)
 	return result
 }
+
+// castOperand はキャスト（(array) 等）の後ろに続けて書ける記述を返す（二項演算・三項演算子は括弧で囲む）。
+func castOperand(src string) string {
+	node, err := parsePHPExpr(src)
+	if err != nil {
+		return "(" + src + ")"
+	}
+	return groupedSrc(node, phpUnaryPrecedence)
+}
@@ -1,27 +1,15 @@
 // choices_select.go: セレクトボックス要素の置換ロジック。
 package ffr
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+)
 
 // --- Select ---
 // replaceFormSelect は Blade 内の Form::select(...) を HTML に置換する。
 func replaceFormSelect(text string) string {
-	patterns := []string{
-		`(?s)\{\{\s*Form::select\(\s*(.*?)\s*\)\s*\}\}`,
-		`(?s)\{\!\!\s*Form::select\(\s*(.*?)\s*\)\s*\!\!\}`,
-	}
-	for _, pattern := range patterns {
-		re := regexCache.GetRegex(pattern)
-		text = re.ReplaceAllStringFunc(text, func(match string) string {
-			fullMatch := re.FindStringSubmatch(match)
-			if len(fullMatch) > 1 {
-				params := extractParamsBalanced(fullMatch[1])
-				return processFormSelect(params)
-			}
-			return match
-		})
-	}
-	return text
+	return replaceFormMethod(text, "select")
 }
 
 // processFormSelect は Select 要素の属性・selected を解決して最終HTMLを生成する。
@@ -31,25 +19,76 @@ func processFormSelect(params []string) string {
 	}
 	name := ProcessFieldName(params[0])
 	options := params[1]
-	selected := ""
+	selectedAttr := ""
 	if len(params) > 2 {
-		selected = params[2]
+		selected := conditionSource(params[2])
+		switch {
+		case selected == "":
+		case strings.HasSuffix(name, "[]") || len(params) > 3 && isMultipleSelect(params[3]):
+			// 複数選択では選択値（old() やモデルの値を含む）を配列として扱う
+			selectedAttr = fmt.Sprintf(" @if(in_array($key, (array)%s)) selected @endif", castOperand(selected))
+		default:
+			selectedAttr = fmt.Sprintf(" @if($key == %s) selected @endif", comparisonOperand(selected))
+		}
 	}
-	attrProcessor := &AttributeProcessor{
-		Order: []string{"class", "id", "onchange"},
-		Patterns: map[string]string{
-			"class":    `'class'\s*=>\s*'([^']+)'`,
-			"id":       `'id'\s*=>\s*'([^']+)'`,
-			"onchange": `'(?:onChange|onchange)'\s*=>\s*'([^']+)'`,
-		},
-	}
-	extraAttrs := ""
+	extraAttrs, placeholder := "", ""
 	if len(params) > 3 {
-		extraAttrs = attrProcessor.ProcessAttributes(params[3])
+		extraAttrs, placeholder = selectAttributes(params[3]), placeholderOption(params[3])
 	}
 	return fmt.Sprintf(`<select name="%s"%s>
-@foreach(%s as $key => $value)
-<option value="{{ $key }}" @if($key == %s) selected @endif>{{ $value }}</option>
+%s@foreach(%s as $key => $value)
+<option value="{{ $key }}"%s>{{ $value }}</option>
 @endforeach
-</select>`, name, extraAttrs, options, selected)
+</select>`, name, extraAttrs, placeholder, options, selectedAttr)
+}
+
+// isMultipleSelect はオプション配列に multiple（['multiple'] や 'multiple' => true 等）があるかを返す。
+func isMultipleSelect(attrs string) bool {
+	options := parseOptionsArray(attrs)
+	if options == nil {
+		return false
+	}
+	if hasFlagOption(options, "multiple") {
+		return true
+	}
+	value := arrayOption(options, "multiple")
+	return value != nil && !value.isConst("false") && !value.isConst("null")
+}
+
+// comparisonOperand は == の右辺に書ける記述を返す（優先順位の低い演算は括弧で囲む）。
+func comparisonOperand(src string) string {
+	node, err := parsePHPExpr(src)
+	if err != nil {
+		return "(" + src + ")"
+	}
+	return groupedSrc(node, phpBinaryPrecedence["=="])
+}
+
+// selectAttributes は select 要素の追加属性を整形する（selectRange 等と共通）。
+// placeholder は属性にせず、placeholderOption で先頭の option として出力する。
+func selectAttributes(attrs string) string {
+	attrProcessor := &AttributeProcessor{
+		Order: attributeOrder("select", []string{"class", "id", "onchange"}),
+		Values: map[string]attrValue{
+			"class":    attrText,
+			"id":       attrText,
+			"onchange": attrText,
+		},
+		Skip: []string{"name", "placeholder"},
+	}
+	return attrProcessor.ProcessAttributes(attrs)
+}
+
+// placeholderOption はオプション配列に placeholder があれば、Collective と同じく
+// 値が空の先頭の option を改行付きで返す（なければ空文字）。
+func placeholderOption(attrs string) string {
+	value := arrayOption(parseOptionsArray(attrs), "placeholder")
+	if value == nil || value.isConst("false") || value.isConst("null") {
+		return ""
+	}
+	text, ok := attributeValue(value, attrLiteral)
+	if !ok {
+		return ""
+	}
+	return fmt.Sprintf("<option value=\"\">%s</option>\n", text)
 }
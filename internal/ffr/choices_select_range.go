@@ -0,0 +1,201 @@
+// choices_select_range.go: 数値の範囲・年・月のセレクトボックス（selectRange / selectYear / selectMonth）の置換ロジック。
+package ffr
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// maxStaticRangeOptions は範囲の option を列挙して出力する上限（超える場合は @foreach で出力する）
+const maxStaticRangeOptions = 1000
+
+// strftimeMonthFormats は selectMonth の書式で使える strftime の指定子と、対応する date() の書式文字
+var strftimeMonthFormats = map[byte]string{
+	'B': "F", 'b': "M", 'h': "M", 'm': "m", 'Y': "Y", 'y': "y", '%': "%",
+}
+
+// replaceFormSelectRange は Blade 内の Form::selectRange(...) を HTML に置換する。
+func replaceFormSelectRange(text string) string {
+	return replaceFormMethod(text, "selectrange")
+}
+
+// replaceFormSelectYear は Blade 内の Form::selectYear(...) を HTML に置換する。
+func replaceFormSelectYear(text string) string {
+	return replaceFormMethod(text, "selectyear")
+}
+
+// replaceFormSelectMonth は Blade 内の Form::selectMonth(...) を HTML に置換する。
+func replaceFormSelectMonth(text string) string {
+	return replaceFormMethod(text, "selectmonth")
+}
+
+// handleFormSelectRange は Form::selectRange / Form::selectYear を select 要素にする。
+// 範囲の両端が整数リテラルなら option を列挙し、式なら PHP の range() の @foreach で出力する
+// （ループ変数 $__v はビューの変数と重ならない名前にする）。
+func handleFormSelectRange(args string) (string, bool) {
+	params, ok := positionalArgs(args)
+	if !ok || len(params) < 3 || len(params) > 5 {
+		return "", false
+	}
+	begin, end := params[1], params[2]
+	selected := optionalParam(params, 3)
+	extraAttrs, placeholder := "", ""
+	if len(params) > 4 {
+		extraAttrs, placeholder = selectAttributes(params[4].Src), placeholderOption(params[4].Src)
+	}
+	var options string
+	first, isFirstInt := integerValue(begin)
+	last, isLastInt := integerValue(end)
+	if isFirstInt && isLastInt && abs(last-first) < maxStaticRangeOptions {
+		options = staticRangeOptions(first, last, selected)
+	} else {
+		options = fmt.Sprintf(`@foreach(range(%s, %s) as $__v)
+<option value="{{ $__v }}"%s>{{ $__v }}</option>
+@endforeach`, begin.Src, end.Src, selectedMarkup("$__v", selected))
+	}
+	return fmt.Sprintf("<select name=\"%s\"%s>\n%s%s\n</select>", ProcessFieldName(params[0].Src), extraAttrs, placeholder, options), true
+}
+
+// handleFormSelectMonth は Form::selectMonth を 1〜12 月の select 要素にする。
+// 月名は Carbon の translatedFormat で出力する（書式・ロケールは month_format / month_locale）。
+// 引数の書式は strftime の指定子（%B 等）を date() の書式に変換し、変換できない場合は変換しない。
+func handleFormSelectMonth(args string) (string, bool) {
+	params, ok := positionalArgs(args)
+	if !ok || len(params) < 1 || len(params) > 4 {
+		return "", false
+	}
+	format := settings.MonthFormat
+	if len(params) > 3 {
+		strftime, ok := params[3].stringLiteral()
+		if !ok {
+			return "", false
+		}
+		if format, ok = strftimeToDateFormat(strftime); !ok {
+			return "", false
+		}
+	}
+	extraAttrs, placeholder := "", ""
+	if len(params) > 2 {
+		extraAttrs, placeholder = selectAttributes(params[2].Src), placeholderOption(params[2].Src)
+	}
+	date := `\Illuminate\Support\Carbon::create(null, $__v, 1)`
+	if settings.MonthLocale != "" {
+		date += fmt.Sprintf("->locale(%s)", phpSingleQuote(settings.MonthLocale))
+	}
+	return fmt.Sprintf(`<select name="%s"%s>
+%s@foreach(range(1, 12) as $__v)
+<option value="{{ $__v }}"%s>{{ %s->translatedFormat(%s) }}</option>
+@endforeach
+</select>`, ProcessFieldName(params[0].Src), extraAttrs, placeholder, selectedMarkup("$__v", optionalParam(params, 1)), date, phpSingleQuote(format)), true
+}
+
+// staticRangeOptions は first から last まで（降順も可）の option を列挙する。
+// selected がリテラルなら一致する option に selected を付け、式なら @if で判定する。
+func staticRangeOptions(first, last int, selected *phpNode) string {
+	step := 1
+	if first > last {
+		step = -1
+	}
+	var lines []string
+	for value := first; ; value += step {
+		text := strconv.Itoa(value)
+		var mark string
+		if literal, ok := selectedLiteral(selected); ok {
+			if literal == text {
+				mark = " selected"
+			}
+		} else {
+			mark = selectedMarkup(text, selected)
+		}
+		lines = append(lines, fmt.Sprintf(`<option value="%s"%s>%s</option>`, text, mark, text))
+		if value == last {
+			break
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// selectedMarkup は値 value の option を selected にする @if を返す（selected が省略・null なら空文字）。
+func selectedMarkup(value string, selected *phpNode) string {
+	if selected == nil || selected.isConst("null") {
+		return ""
+	}
+	return fmt.Sprintf(" @if(%s == %s) selected @endif", value, groupedSrc(selected, phpBinaryPrecedence["=="]))
+}
+
+// selectedLiteral は selected が文字列・数値のリテラルなら、文字列にした値を返す（Collective と同じく文字列で比較する）。
+func selectedLiteral(selected *phpNode) (string, bool) {
+	if selected == nil {
+		return "", false
+	}
+	if text, ok := selected.stringLiteral(); ok {
+		return text, true
+	}
+	return selected.Value, isIntegerLiteral(selected)
+}
+
+// optionalParam は i 番目の引数を返す（省略されていれば nil）。
+func optionalParam(params []*phpNode, i int) *phpNode {
+	if i < len(params) {
+		return params[i]
+	}
+	return nil
+}
+
+// integerValue は node が整数リテラル（負の数を含む）なら、その値を返す。
+func integerValue(node *phpNode) (int, bool) {
+	sign := 1
+	if node.Kind == phpUnary && node.Value == "-" {
+		sign, node = -1, node.Children[0]
+	}
+	if !isIntegerLiteral(node) {
+		return 0, false
+	}
+	value, err := strconv.Atoi(node.Value)
+	return sign * value, err == nil
+}
+
+// groupedSrc は node を優先順位 precedence の二項演算子のオペランドとして書けるソースを返す（必要なら括弧で囲む）。
+// 代入は phpBinaryPrecedence にないため常に括弧で囲む。
+func groupedSrc(node *phpNode, precedence int) string {
+	if node.Kind == phpTernary || node.Kind == phpBinary && phpBinaryPrecedence[node.Value] <= precedence {
+		return "(" + node.Src + ")"
+	}
+	return node.Src
+}
+
+// strftimeToDateFormat は strftime の書式（%B 等）を date() の書式に変換する。
+// 月名・月・年以外の指定子を含む場合は ok=false。
+func strftimeToDateFormat(format string) (string, bool) {
+	var b strings.Builder
+	for i := 0; i < len(format); i++ {
+		c := format[i]
+		switch {
+		case c == '%':
+			if i++; i == len(format) {
+				return "", false
+			}
+			converted, ok := strftimeMonthFormats[format[i]]
+			if !ok {
+				return "", false
+			}
+			b.WriteString(converted)
+		case c == '\\' || 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z':
+			// date() の書式文字にならないようエスケープする
+			b.WriteByte('\\')
+			b.WriteByte(c)
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return b.String(), true
+}
+
+// abs は整数の絶対値を返す。
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
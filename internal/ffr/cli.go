@@ -2,63 +2,369 @@ package ffr
 
 import (
 	"fmt"
+	"io"
 	"log"
 	"os"
+	"path/filepath"
+	"strconv"
 	"strings"
 )
 
+// cliAction は引数解析の結果として実行する処理を表す。
+type cliAction int
+
+const (
+	actionRun cliAction = iota
+	actionHelp
+	actionVersion
+)
+
 // Run はCLIエントリポイント。戻り値はプロセス終了コード。
 func Run(args []string) int {
-	config := &ReplacementConfig{
-		TargetPath:     "",
-		IsFile:         false,
-		ProcessedFiles: make([]string, 0),
-		FileCount:      0,
-	}
+	return RunWith(args, os.Stdin, os.Stdout, os.Stderr)
+}
+
+// RunWith は入出力を指定して Run と同じ処理をする（ライブラリからの呼び出しやテスト向け）。
+// 変換結果・レポートとメッセージは stdout へ、エラーは stderr へ書き出す。
+// レポートを stdout へ書き出す場合、メッセージは stderr へ回す。
+func RunWith(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	config := newReplacementConfig()
+	config.out = stdout
+	config.logger = log.New(stderr, "", log.LstdFlags)
 
 	if len(args) < 2 {
-		fmt.Println("エラー: ファイルまたはディレクトリを指定してください。")
-		printUsage()
+		fmt.Fprintln(config.messages(), "エラー: ファイルまたはディレクトリを指定してください。")
+		printUsage(config.messages())
 		return 1
 	}
 
-	arg := args[1]
-	if arg == "--help" || arg == "-h" {
-		printUsage()
-		return 0
+	if args[1] == "restore" {
+		return runRestore(args[2:], stdout, config.log())
 	}
 
-	if arg == "--version" || arg == "-v" {
-		printVersion()
+	action, err := parseArgs(args[1:], config)
+	if err != nil {
+		fmt.Fprintf(config.messages(), "エラー: %v\n", err)
+		printUsage(config.messages())
+		return 1
+	}
+	switch action {
+	case actionHelp:
+		printUsage(config.messages())
+		return 0
+	case actionVersion:
+		printVersion(config.messages())
 		return 0
 	}
 
-	config.TargetPath = arg
+	// チェックモードでは「書き換えが必要」(1) と区別できるようエラーを専用コードで返す
+	failCode := 1
+	if config.Check {
+		failCode = exitCheckError
+	}
+
+	// レポートを標準出力へ書き出す場合、人間向けのメッセージは標準エラーへ回す
+	if config.reportsToStdout() {
+		config.out = stderr
+	}
+
+	if err := applyConfigFile(config); err != nil {
+		config.log().Printf("エラー: 設定ファイルを読み込めません: %v", err)
+		return failCode
+	}
+
+	if config.Stdin {
+		// 標準出力は変換結果専用のため、メッセージはすべて標準エラー（log）へ出す
+		if err := processStdin(config, stdin, stdout); err != nil {
+			config.log().Printf("エラー: %v", err)
+			return failCode
+		}
+		if config.PatchFile != "" {
+			if err := writePatchFile(config); err != nil {
+				config.log().Printf("パッチファイル '%s' の書き込みに失敗しました: %v", config.PatchFile, err)
+				return failCode
+			}
+		}
+		if config.Report != "" {
+			if err := emitReport(config, stdout); err != nil {
+				config.log().Printf("レポートの書き込みに失敗しました: %v", err)
+				return failCode
+			}
+		}
+		if config.Check {
+			return printCheckReport(config)
+		}
+		return 0
+	}
 
 	info, err := os.Stat(config.TargetPath)
 	if err != nil {
-		log.Printf("エラー: '%s' が存在しません。", config.TargetPath)
-		return 1
+		config.log().Printf("エラー: '%s' が存在しません。", config.TargetPath)
+		return failCode
 	}
 
 	config.IsFile = !info.IsDir()
 
-	if config.IsFile {
-		if !strings.HasSuffix(config.TargetPath, ".blade.php") {
-			log.Printf("エラー: '%s' は.blade.phpファイルではありません。", config.TargetPath)
-			return 1
+	if config.IsFile && !hasTargetSuffix(config.TargetPath) {
+		config.log().Printf("エラー: '%s' は対象の拡張子（%s）のファイルではありません。", config.TargetPath, describeSuffixes())
+		return failCode
+	}
+	if config.usesGit() {
+		if err := selectGitFiles(config); err != nil {
+			config.log().Printf("エラー: %v", err)
+			return failCode
 		}
-		fmt.Printf("Form Facade置換を開始します (ファイル): %s\n", config.TargetPath)
-	} else {
-		fmt.Printf("Form Facade置換を開始します (ディレクトリ): %s\n", config.TargetPath)
+		if config.writesFiles() && !config.Force {
+			if err := checkCleanWorkingTree(config); err != nil {
+				config.log().Printf("エラー: %v", err)
+				return failCode
+			}
+		}
+	}
+	if config.showsProgress() {
+		if config.IsFile {
+			fmt.Fprintf(config.messages(), "Form Facade置換を開始します (ファイル): %s\n", config.TargetPath)
+		} else {
+			fmt.Fprintf(config.messages(), "Form Facade置換を開始します (ディレクトリ): %s\n", config.TargetPath)
+		}
+	}
+	if config.DryRun {
+		fmt.Fprintln(config.messages(), "dry-run モード: ファイルは書き換えず差分のみ表示します")
+	}
+	if config.usesGit() && config.showsProgress() {
+		fmt.Fprintf(config.messages(), "git の変更から対象ファイルを絞り込みました: %d 件\n", len(config.gitPaths))
 	}
 
 	err = processBladeFiles(config)
 	if err != nil {
-		log.Printf("ファイル処理中にエラーが発生しました: %v", err)
-		return 1
+		config.log().Printf("ファイル処理中にエラーが発生しました: %v", err)
+		return failCode
+	}
+
+	if config.PatchFile != "" {
+		if err := writePatchFile(config); err != nil {
+			config.log().Printf("パッチファイル '%s' の書き込みに失敗しました: %v", config.PatchFile, err)
+			return failCode
+		}
+		fmt.Fprintf(config.messages(), "パッチファイルを書き出しました: %s\n", config.PatchFile)
+	}
+
+	if config.Report != "" {
+		if err := emitReport(config, stdout); err != nil {
+			config.log().Printf("レポートの書き込みに失敗しました: %v", err)
+			return failCode
+		}
+		if config.ReportFile != "" {
+			fmt.Fprintf(config.messages(), "レポートを書き出しました: %s\n", config.ReportFile)
+		}
 	}
 
+	if config.Check {
+		return printCheckReport(config)
+	}
 	printSummary(config)
+	if config.backup != nil {
+		fmt.Fprintf(config.messages(), "バックアップを保存しました: %s（元に戻す: restore %s）\n", config.backup.Dir, config.backup.Manifest.ID)
+	}
 	return 0
 }
+
+// parseArgs はコマンドライン引数（プログラム名を除く）を解析して config に反映する。
+func parseArgs(args []string, config *ReplacementConfig) (cliAction, error) {
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		name, value, hasValue := arg, "", false
+		if strings.HasPrefix(arg, "--") {
+			name, value, hasValue = strings.Cut(arg, "=")
+		} else if strings.HasPrefix(arg, "-U") && len(arg) > 2 {
+			name, value, hasValue = "-U", arg[2:], true
+		} else if strings.HasPrefix(arg, "-j") && len(arg) > 2 {
+			name, value, hasValue = "-j", arg[2:], true
+		}
+
+		switch name {
+		case "-h", "--help":
+			return actionHelp, nil
+		case "-v", "--version":
+			return actionVersion, nil
+		case "--dry-run", "--diff":
+			config.DryRun = true
+		case "-", "--stdin":
+			config.Stdin = true
+		case "--stdin-filename":
+			v, err := optionValue(args, &i, name, value, hasValue)
+			if err != nil {
+				return actionRun, err
+			}
+			config.StdinFilename = v
+		case "--transaction", "--all-or-nothing":
+			config.Transaction = true
+		case "--backup":
+			config.Backup = true
+		case "--backup-dir":
+			v, err := optionValue(args, &i, name, value, hasValue)
+			if err != nil {
+				return actionRun, err
+			}
+			config.BackupDir = v
+			config.Backup = true
+		case "--include", "--exclude":
+			v, err := optionValue(args, &i, name, value, hasValue)
+			if err != nil {
+				return actionRun, err
+			}
+			if err := validateGlob(v); err != nil {
+				return actionRun, err
+			}
+			if name == "--include" {
+				config.Include = append(config.Include, v)
+			} else {
+				config.Exclude = append(config.Exclude, v)
+			}
+		case "--no-ignore":
+			config.NoIgnore = true
+		case "--config":
+			v, err := optionValue(args, &i, name, value, hasValue)
+			if err != nil {
+				return actionRun, err
+			}
+			config.ConfigPath = v
+		case "--check":
+			config.Check = true
+		case "--color":
+			config.ColorDiff = true
+		case "-U", "--diff-context":
+			v, err := optionValue(args, &i, name, value, hasValue)
+			if err != nil {
+				return actionRun, err
+			}
+			n, err := strconv.Atoi(v)
+			if err != nil || n < 0 {
+				return actionRun, fmt.Errorf("%s には0以上の整数を指定してください: %s", name, v)
+			}
+			config.DiffContext = n
+		case "--report":
+			v, err := optionValue(args, &i, name, value, hasValue)
+			if err != nil {
+				return actionRun, err
+			}
+			if v != reportFormatJSON {
+				return actionRun, fmt.Errorf("%s には %s を指定してください: %s", name, reportFormatJSON, v)
+			}
+			config.Report = v
+		case "--report-file":
+			v, err := optionValue(args, &i, name, value, hasValue)
+			if err != nil {
+				return actionRun, err
+			}
+			config.ReportFile = v
+			if config.Report == "" {
+				config.Report = reportFormatJSON
+			}
+		case "--git-changed":
+			v, err := optionValue(args, &i, name, value, hasValue)
+			if err != nil {
+				return actionRun, err
+			}
+			if err := validateGitRef(v); err != nil {
+				return actionRun, err
+			}
+			config.GitChanged = v
+		case "--git-staged":
+			config.GitStaged = true
+		case "--force":
+			config.Force = true
+		case "-j", "--jobs":
+			v, err := optionValue(args, &i, name, value, hasValue)
+			if err != nil {
+				return actionRun, err
+			}
+			n, err := strconv.Atoi(v)
+			if err != nil || n < 1 {
+				return actionRun, fmt.Errorf("%s には1以上の整数を指定してください: %s", name, v)
+			}
+			config.Jobs = n
+		case "--patch":
+			v, err := optionValue(args, &i, name, value, hasValue)
+			if err != nil {
+				return actionRun, err
+			}
+			config.PatchFile = v
+			config.DryRun = true
+		default:
+			if strings.HasPrefix(arg, "-") {
+				return actionRun, fmt.Errorf("不明なオプションです: %s", arg)
+			}
+			if config.TargetPath != "" {
+				return actionRun, fmt.Errorf("対象は1つだけ指定できます: %s", arg)
+			}
+			config.TargetPath = arg
+		}
+	}
+
+	if config.GitChanged != "" && config.GitStaged {
+		return actionRun, fmt.Errorf("--git-changed と --git-staged は同時に指定できません")
+	}
+	if config.Stdin {
+		if config.usesGit() {
+			return actionRun, fmt.Errorf("標準入力モードでは --git-changed / --git-staged を使用できません")
+		}
+		if config.reportsToStdout() {
+			return actionRun, fmt.Errorf("標準入力モードではレポートを標準出力に書き出せません（--report-file を指定してください）")
+		}
+		if config.TargetPath != "" {
+			return actionRun, fmt.Errorf("標準入力モードではファイルやディレクトリを指定できません: %s", config.TargetPath)
+		}
+		return actionRun, nil
+	}
+	if config.StdinFilename != "" {
+		return actionRun, fmt.Errorf("--stdin-filename は標準入力モード（- または --stdin）でのみ使用できます")
+	}
+	if config.TargetPath == "" {
+		return actionRun, fmt.Errorf("ファイルまたはディレクトリを指定してください")
+	}
+	return actionRun, nil
+}
+
+// optionValue は "--name=value" または "--name value" 形式のオプション値を取得する。
+func optionValue(args []string, i *int, name, value string, hasValue bool) (string, error) {
+	if hasValue {
+		return value, nil
+	}
+	if *i+1 >= len(args) {
+		return "", fmt.Errorf("%s には値が必要です", name)
+	}
+	*i++
+	return args[*i], nil
+}
+
+// applyConfigFile は --config 指定または対象パスから遡って見つけた設定ファイルを読み込み、変換設定に反映する。
+func applyConfigFile(config *ReplacementConfig) error {
+	path := config.ConfigPath
+	if path == "" {
+		start := config.TargetPath
+		if config.Stdin {
+			start = "."
+			if config.StdinFilename != "" {
+				start = filepath.Dir(config.StdinFilename)
+			}
+		}
+		path = findConfigFile(start)
+	}
+	if path == "" {
+		settings = defaultSettings()
+		return nil
+	}
+	loaded, err := loadSettings(path)
+	if err != nil {
+		return err
+	}
+	settings = loaded
+	// 標準入力モードでは標準出力を汚さないよう標準エラーへ出す
+	if config.Stdin {
+		config.log().Printf("設定ファイルを読み込みました: %s", path)
+	} else if config.showsProgress() {
+		fmt.Fprintf(config.messages(), "設定ファイルを読み込みました: %s\n", path)
+	}
+	return nil
+}
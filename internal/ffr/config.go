@@ -0,0 +1,297 @@
+// config.go: プロジェクト設定ファイル（.ffr.yaml / .ffr.json）の探索・検証と変換設定。
+package ffr
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// 探索する設定ファイル名（同じディレクトリに複数ある場合は先頭を優先）
+var configFileNames = []string{".ffr.yaml", ".ffr.yml", ".ffr.json"}
+
+// Settings は設定ファイルで変更できる変換設定。
+type Settings struct {
+	// Suffixes は変換対象とするファイルの拡張子
+	Suffixes []string
+	// CSRFField は GET 以外のフォームに出力する CSRF ヘルパー
+	CSRFField string
+	// ValueFormat は値を Blade に埋め込む書式（%s が式に置き換わる）
+	ValueFormat string
+	// AttributeOrder は要素ごとの属性の出力順（未指定の要素は既定の順序）
+	AttributeOrder map[string][]string
+	// Include / Exclude はディレクトリ走査時の絞り込みグロブ（コマンドラインの指定に追加される）
+	Include []string
+	Exclude []string
+	// FacadeAliases は Form / \Collective\Html\FormFacade のほかに Form ファサードとして扱うクラス名
+	FacadeAliases []string
+	// MethodField は PUT / PATCH / DELETE のフォームに出力する _method の形式（directive: @method(...) / input: hidden の input）
+	MethodField string
+	// OldInput は値・選択状態・チェック状態を old() で囲み、バリデーション失敗時に入力値を復元するか
+	OldInput bool
+	// MonthFormat は Form::selectMonth の月名の書式（Carbon の translatedFormat に渡す date() の書式）
+	MonthFormat string
+	// MonthLocale は Form::selectMonth の月名のロケール（空ならアプリケーションのロケール）
+	MonthLocale string
+}
+
+// defaultSettings は設定ファイルがない場合の既定値を返す。
+func defaultSettings() *Settings {
+	return &Settings{
+		Suffixes:       []string{".blade.php"},
+		CSRFField:      "{{ csrf_field() }}",
+		ValueFormat:    "{{ %s }}",
+		MethodField:    "directive",
+		MonthFormat:    "F",
+		AttributeOrder: map[string][]string{},
+	}
+}
+
+// settings は現在の変換設定（Run の開始時に設定ファイルの内容で置き換える）
+var settings = defaultSettings()
+
+// attributeOrderElements は attribute_order で指定できる要素名
+var attributeOrderElements = []string{
+	"button", "checkbox", "file", "form", "hidden", "input", "label",
+	"number", "password", "radio", "select", "submit", "textarea",
+}
+
+// attributeOrder は要素の属性出力順を返す（設定がなければ defaults）。
+func attributeOrder(element string, defaults []string) []string {
+	if order, ok := settings.AttributeOrder[element]; ok {
+		return order
+	}
+	return defaults
+}
+
+// formatBladeValue は PHP 式を ValueFormat に従って Blade の出力に変換する。
+func formatBladeValue(expr string) string {
+	return strings.Replace(settings.ValueFormat, "%s", expr, 1)
+}
+
+// hasTargetSuffix は path が変換対象の拡張子を持つかを返す。
+func hasTargetSuffix(path string) bool {
+	for _, suffix := range settings.Suffixes {
+		if strings.HasSuffix(path, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// findConfigFile は start から親ディレクトリへ遡って設定ファイルを探す（見つからなければ空文字）。
+func findConfigFile(start string) string {
+	dir, err := filepath.Abs(start)
+	if err != nil {
+		return ""
+	}
+	if info, err := os.Stat(dir); err == nil && !info.IsDir() {
+		dir = filepath.Dir(dir)
+	}
+	for {
+		for _, name := range configFileNames {
+			candidate := filepath.Join(dir, name)
+			if info, err := os.Stat(candidate); err == nil && !info.IsDir() {
+				return candidate
+			}
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+// loadSettings は設定ファイルを読み込み、検証済みの Settings を返す。
+func loadSettings(path string) (*Settings, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var root *configNode
+	if strings.HasSuffix(path, ".json") {
+		root, err = parseConfigJSON(path, data)
+	} else {
+		root, err = parseConfigYAML(path, data)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return decodeSettings(path, root)
+}
+
+// decodeSettings は設定ノードを検証して Settings に反映する。
+func decodeSettings(path string, root *configNode) (*Settings, error) {
+	s := defaultSettings()
+	if root.Kind == configNull {
+		return s, nil
+	}
+	if root.Kind != configMapping {
+		return nil, &configError{path, root.Line, "設定ファイルのトップレベルはマッピングで記述してください"}
+	}
+
+	for _, entry := range root.Entries {
+		value := entry.Value
+		switch entry.Key {
+		case "suffixes":
+			list, err := configStringList(path, entry)
+			if err != nil {
+				return nil, err
+			}
+			if len(list) == 0 {
+				return nil, &configError{path, entry.Line, "suffixes には1つ以上の拡張子を指定してください"}
+			}
+			s.Suffixes = list
+		case "csrf":
+			v, err := configString(path, entry)
+			if err != nil {
+				return nil, err
+			}
+			if strings.TrimSpace(v) == "" {
+				return nil, &configError{path, value.Line, "csrf は空にできません"}
+			}
+			s.CSRFField = v
+		case "value_format":
+			v, err := configString(path, entry)
+			if err != nil {
+				return nil, err
+			}
+			if strings.Count(v, "%s") != 1 {
+				return nil, &configError{path, value.Line, fmt.Sprintf("value_format には %%s をちょうど1つ含めてください: %q", v)}
+			}
+			s.ValueFormat = v
+		case "include", "exclude":
+			list, err := configStringList(path, entry)
+			if err != nil {
+				return nil, err
+			}
+			for i, pattern := range list {
+				if err := validateGlob(pattern); err != nil {
+					return nil, &configError{path, value.Items[i].Line, err.Error()}
+				}
+			}
+			if entry.Key == "include" {
+				s.Include = list
+			} else {
+				s.Exclude = list
+			}
+		case "facade_aliases":
+			list, err := configStringList(path, entry)
+			if err != nil {
+				return nil, err
+			}
+			for i, name := range list {
+				if !isValidFacadeName(name) {
+					return nil, &configError{path, value.Items[i].Line, fmt.Sprintf("facade_aliases の要素 %q はクラス名として正しくありません", name)}
+				}
+			}
+			s.FacadeAliases = list
+		case "method_field":
+			v, err := configString(path, entry)
+			if err != nil {
+				return nil, err
+			}
+			if v != "directive" && v != "input" {
+				return nil, &configError{path, value.Line, fmt.Sprintf("method_field には directive か input を指定してください: %q", v)}
+			}
+			s.MethodField = v
+		case "old_input":
+			v, err := configBool(path, entry)
+			if err != nil {
+				return nil, err
+			}
+			s.OldInput = v
+		case "month_format":
+			v, err := configString(path, entry)
+			if err != nil {
+				return nil, err
+			}
+			if v == "" {
+				return nil, &configError{path, value.Line, "month_format は空にできません"}
+			}
+			s.MonthFormat = v
+		case "month_locale":
+			v, err := configString(path, entry)
+			if err != nil {
+				return nil, err
+			}
+			if !isValidLocale(v) {
+				return nil, &configError{path, value.Line, fmt.Sprintf("month_locale にはロケール名（ja、en_US 等）を指定してください: %q", v)}
+			}
+			s.MonthLocale = v
+		case "attribute_order":
+			if value.Kind != configMapping {
+				return nil, &configError{path, entry.Line, "attribute_order は要素名をキーとするマッピングで記述してください"}
+			}
+			for _, element := range value.Entries {
+				if !containsString(attributeOrderElements, element.Key) {
+					return nil, &configError{path, element.Line, fmt.Sprintf("attribute_order の要素名 %q は不明です（指定可能: %s）", element.Key, strings.Join(attributeOrderElements, ", "))}
+				}
+				order, err := configStringList(path, element)
+				if err != nil {
+					return nil, err
+				}
+				s.AttributeOrder[element.Key] = order
+			}
+		default:
+			return nil, &configError{path, entry.Line, fmt.Sprintf("不明な設定項目です: %s", entry.Key)}
+		}
+	}
+	return s, nil
+}
+
+// configString はスカラー値を文字列として取り出す。
+func configString(path string, entry configEntry) (string, error) {
+	if entry.Value.Kind != configScalar {
+		return "", &configError{path, entry.Line, fmt.Sprintf("%s には文字列を指定してください", entry.Key)}
+	}
+	return entry.Value.Value, nil
+}
+
+// configBool はスカラー値を true / false として取り出す。
+func configBool(path string, entry configEntry) (bool, error) {
+	if entry.Value.Kind != configScalar || entry.Value.Value != "true" && entry.Value.Value != "false" {
+		return false, &configError{path, entry.Line, fmt.Sprintf("%s には true か false を指定してください", entry.Key)}
+	}
+	return entry.Value.Value == "true", nil
+}
+
+// configStringList は文字列のシーケンスを取り出す（空文字と重複はエラー）。
+func configStringList(path string, entry configEntry) ([]string, error) {
+	if entry.Value.Kind != configSequence {
+		return nil, &configError{path, entry.Line, fmt.Sprintf("%s には文字列のリストを指定してください", entry.Key)}
+	}
+	list := make([]string, 0, len(entry.Value.Items))
+	for _, item := range entry.Value.Items {
+		if item.Kind != configScalar || strings.TrimSpace(item.Value) == "" {
+			return nil, &configError{path, item.Line, fmt.Sprintf("%s の要素には空でない文字列を指定してください", entry.Key)}
+		}
+		if containsString(list, item.Value) {
+			return nil, &configError{path, item.Line, fmt.Sprintf("%s の要素 %q が重複しています", entry.Key, item.Value)}
+		}
+		list = append(list, item.Value)
+	}
+	return list, nil
+}
+
+// containsString は list に s が含まれるかを返す。
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// describeSuffixes は対象拡張子をメッセージ用に連結する。
+func describeSuffixes() string {
+	return strings.Join(settings.Suffixes, ", ")
+}
+
+// isValidLocale は name がロケール名（ja / en_US / zh-Hant-TW 等）の形かを返す。
+func isValidLocale(name string) bool {
+	return regexCache.GetRegex(`^[A-Za-z]{2,3}([_-][A-Za-z0-9]+)*$`).MatchString(name)
+}
@@ -0,0 +1,414 @@
+// config_parse.go: 設定ファイル（YAML サブセット / JSON）を行番号付きのノードへ読み込むパーサ。
+package ffr
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// configKind は設定ノードの種類。
+type configKind int
+
+const (
+	configScalar configKind = iota
+	configMapping
+	configSequence
+	configNull
+)
+
+// configNode は設定ファイルの値を表す（行番号はエラー報告に使う）。
+type configNode struct {
+	Kind    configKind
+	Line    int
+	Value   string        // configScalar の値
+	Entries []configEntry // configMapping の要素（記述順）
+	Items   []*configNode // configSequence の要素
+}
+
+// configEntry はマッピングの1要素。
+type configEntry struct {
+	Key   string
+	Line  int
+	Value *configNode
+}
+
+// configError は設定ファイルの行番号付きエラー。
+type configError struct {
+	Path string
+	Line int
+	Msg  string
+}
+
+func (e *configError) Error() string {
+	if e.Line > 0 {
+		return fmt.Sprintf("%s:%d: %s", e.Path, e.Line, e.Msg)
+	}
+	return fmt.Sprintf("%s: %s", e.Path, e.Msg)
+}
+
+// --- JSON ---
+
+// parseConfigJSON は JSON を configNode に変換する。
+func parseConfigJSON(path string, data []byte) (*configNode, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	lineAt := func(offset int64) int {
+		return bytes.Count(data[:offset], []byte("\n")) + 1
+	}
+
+	var parseValue func() (*configNode, error)
+	parseValue = func() (*configNode, error) {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, &configError{path, lineAt(dec.InputOffset()), fmt.Sprintf("JSON の解析に失敗しました: %v", err)}
+		}
+		line := lineAt(dec.InputOffset())
+		switch v := tok.(type) {
+		case json.Delim:
+			switch v {
+			case '{':
+				node := &configNode{Kind: configMapping, Line: line}
+				for dec.More() {
+					keyTok, err := dec.Token()
+					if err != nil {
+						return nil, &configError{path, lineAt(dec.InputOffset()), fmt.Sprintf("JSON の解析に失敗しました: %v", err)}
+					}
+					key, _ := keyTok.(string)
+					keyLine := lineAt(dec.InputOffset())
+					value, err := parseValue()
+					if err != nil {
+						return nil, err
+					}
+					node.Entries = append(node.Entries, configEntry{Key: key, Line: keyLine, Value: value})
+				}
+				if _, err := dec.Token(); err != nil {
+					return nil, &configError{path, lineAt(dec.InputOffset()), fmt.Sprintf("JSON の解析に失敗しました: %v", err)}
+				}
+				return node, nil
+			case '[':
+				node := &configNode{Kind: configSequence, Line: line}
+				for dec.More() {
+					item, err := parseValue()
+					if err != nil {
+						return nil, err
+					}
+					node.Items = append(node.Items, item)
+				}
+				if _, err := dec.Token(); err != nil {
+					return nil, &configError{path, lineAt(dec.InputOffset()), fmt.Sprintf("JSON の解析に失敗しました: %v", err)}
+				}
+				return node, nil
+			}
+		case nil:
+			return &configNode{Kind: configNull, Line: line}, nil
+		case string:
+			return &configNode{Kind: configScalar, Line: line, Value: v}, nil
+		case json.Number:
+			return &configNode{Kind: configScalar, Line: line, Value: v.String()}, nil
+		case bool:
+			return &configNode{Kind: configScalar, Line: line, Value: fmt.Sprint(v)}, nil
+		}
+		return nil, &configError{path, line, fmt.Sprintf("予期しないトークンです: %v", tok)}
+	}
+
+	root, err := parseValue()
+	if err != nil {
+		return nil, err
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return nil, &configError{path, lineAt(dec.InputOffset()), "JSON の末尾に余分な内容があります"}
+	}
+	return root, nil
+}
+
+// --- YAML（サブセット） ---
+
+// yamlLine はコメントを除去した YAML の1行。
+type yamlLine struct {
+	Num    int
+	Indent int
+	Text   string
+}
+
+// yamlParser はブロック形式のマッピング/シーケンス、フロー形式のシーケンスとスカラーのみを扱う。
+type yamlParser struct {
+	path  string
+	lines []yamlLine
+	pos   int
+}
+
+// parseConfigYAML は YAML（設定ファイルで使うサブセット）を configNode に変換する。
+func parseConfigYAML(path string, data []byte) (*configNode, error) {
+	p := &yamlParser{path: path}
+	for i, raw := range strings.Split(strings.ReplaceAll(string(data), "\r\n", "\n"), "\n") {
+		text := strings.TrimRight(stripYAMLComment(raw), " \t")
+		if strings.TrimSpace(text) == "" || text == "---" {
+			continue
+		}
+		indent := len(text) - len(strings.TrimLeft(text, " "))
+		if strings.HasPrefix(text[indent:], "\t") {
+			return nil, &configError{path, i + 1, "インデントにタブは使用できません"}
+		}
+		p.lines = append(p.lines, yamlLine{Num: i + 1, Indent: indent, Text: text[indent:]})
+	}
+	if len(p.lines) == 0 {
+		return &configNode{Kind: configMapping, Line: 1}, nil
+	}
+	root, err := p.parseBlock(p.lines[0].Indent)
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.lines) {
+		return nil, p.errorf(p.lines[p.pos].Num, "インデントが不正です")
+	}
+	return root, nil
+}
+
+func (p *yamlParser) errorf(line int, format string, args ...interface{}) error {
+	return &configError{p.path, line, fmt.Sprintf(format, args...)}
+}
+
+// parseBlock は indent の位置から始まるマッピングまたはシーケンスを読み込む。
+func (p *yamlParser) parseBlock(indent int) (*configNode, error) {
+	if isYAMLSequenceItem(p.lines[p.pos].Text) {
+		return p.parseSequence(indent)
+	}
+	return p.parseMapping(indent)
+}
+
+// parseMapping はブロック形式のマッピングを読み込む。
+func (p *yamlParser) parseMapping(indent int) (*configNode, error) {
+	node := &configNode{Kind: configMapping, Line: p.lines[p.pos].Num}
+	for p.pos < len(p.lines) {
+		line := p.lines[p.pos]
+		if line.Indent < indent {
+			break
+		}
+		if line.Indent > indent {
+			return nil, p.errorf(line.Num, "インデントが不正です")
+		}
+		if isYAMLSequenceItem(line.Text) {
+			return nil, p.errorf(line.Num, "マッピングの中にシーケンス要素があります")
+		}
+		key, rest, ok := splitYAMLKey(line.Text)
+		if !ok {
+			return nil, p.errorf(line.Num, "\"キー: 値\" の形式で記述してください: %s", line.Text)
+		}
+		key, err := parseYAMLScalar(key)
+		if err != nil {
+			return nil, p.errorf(line.Num, "%v", err)
+		}
+		for _, entry := range node.Entries {
+			if entry.Key == key {
+				return nil, p.errorf(line.Num, "キー %q が重複しています（%d 行目）", key, entry.Line)
+			}
+		}
+		p.pos++
+
+		var value *configNode
+		switch {
+		case rest != "":
+			value, err = p.parseInlineValue(rest, line.Num)
+			if err != nil {
+				return nil, err
+			}
+		case p.pos < len(p.lines) && p.lines[p.pos].Indent > indent:
+			value, err = p.parseBlock(p.lines[p.pos].Indent)
+			if err != nil {
+				return nil, err
+			}
+		case p.pos < len(p.lines) && p.lines[p.pos].Indent == indent && isYAMLSequenceItem(p.lines[p.pos].Text):
+			// "key:" の直後に同じインデントで "- item" が続く書き方
+			value, err = p.parseSequence(indent)
+			if err != nil {
+				return nil, err
+			}
+		default:
+			value = &configNode{Kind: configNull, Line: line.Num}
+		}
+		node.Entries = append(node.Entries, configEntry{Key: key, Line: line.Num, Value: value})
+	}
+	return node, nil
+}
+
+// parseSequence はブロック形式のシーケンスを読み込む。
+func (p *yamlParser) parseSequence(indent int) (*configNode, error) {
+	node := &configNode{Kind: configSequence, Line: p.lines[p.pos].Num}
+	for p.pos < len(p.lines) {
+		line := p.lines[p.pos]
+		if line.Indent < indent || (line.Indent == indent && !isYAMLSequenceItem(line.Text)) {
+			break
+		}
+		if line.Indent > indent {
+			return nil, p.errorf(line.Num, "インデントが不正です")
+		}
+		rest := strings.TrimSpace(strings.TrimPrefix(line.Text, "-"))
+		p.pos++
+		if rest == "" {
+			if p.pos < len(p.lines) && p.lines[p.pos].Indent > indent {
+				item, err := p.parseBlock(p.lines[p.pos].Indent)
+				if err != nil {
+					return nil, err
+				}
+				node.Items = append(node.Items, item)
+				continue
+			}
+			node.Items = append(node.Items, &configNode{Kind: configNull, Line: line.Num})
+			continue
+		}
+		if _, _, ok := splitYAMLKey(rest); ok && !isYAMLQuoted(rest) {
+			return nil, p.errorf(line.Num, "シーケンス内のマッピングには対応していません")
+		}
+		item, err := p.parseInlineValue(rest, line.Num)
+		if err != nil {
+			return nil, err
+		}
+		node.Items = append(node.Items, item)
+	}
+	return node, nil
+}
+
+// parseInlineValue は "key: value" の value 部分（スカラーまたはフロー形式のシーケンス）を読み込む。
+func (p *yamlParser) parseInlineValue(text string, line int) (*configNode, error) {
+	if strings.HasPrefix(text, "[") {
+		if !strings.HasSuffix(text, "]") {
+			return nil, p.errorf(line, "フロー形式のシーケンスが閉じられていません: %s", text)
+		}
+		node := &configNode{Kind: configSequence, Line: line}
+		inner := strings.TrimSpace(text[1 : len(text)-1])
+		if inner == "" {
+			return node, nil
+		}
+		for _, part := range splitYAMLFlow(inner) {
+			part = strings.TrimSpace(part)
+			if part == "" {
+				continue
+			}
+			if strings.HasPrefix(part, "[") || strings.HasPrefix(part, "{") {
+				return nil, p.errorf(line, "ネストしたフロー形式には対応していません")
+			}
+			value, err := parseYAMLScalar(part)
+			if err != nil {
+				return nil, p.errorf(line, "%v", err)
+			}
+			node.Items = append(node.Items, &configNode{Kind: configScalar, Line: line, Value: value})
+		}
+		return node, nil
+	}
+	if strings.HasPrefix(text, "{") {
+		if text == "{}" {
+			return &configNode{Kind: configMapping, Line: line}, nil
+		}
+		return nil, p.errorf(line, "フロー形式のマッピングには対応していません")
+	}
+	if text == "~" || text == "null" {
+		return &configNode{Kind: configNull, Line: line}, nil
+	}
+	value, err := parseYAMLScalar(text)
+	if err != nil {
+		return nil, p.errorf(line, "%v", err)
+	}
+	return &configNode{Kind: configScalar, Line: line, Value: value}, nil
+}
+
+// isYAMLSequenceItem は行がシーケンス要素（"- "）かを返す。
+func isYAMLSequenceItem(text string) bool {
+	return text == "-" || strings.HasPrefix(text, "- ")
+}
+
+// isYAMLQuoted はテキストがクォートされたスカラーかを返す。
+func isYAMLQuoted(text string) bool {
+	return strings.HasPrefix(text, "'") || strings.HasPrefix(text, `"`)
+}
+
+// stripYAMLComment はクォート外の "#" 以降（行頭または空白の直後）を取り除く。
+func stripYAMLComment(line string) string {
+	var quote byte
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		switch {
+		case quote != 0:
+			if c == '\\' && quote == '"' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"':
+			quote = c
+		case c == '#' && (i == 0 || line[i-1] == ' ' || line[i-1] == '\t'):
+			return line[:i]
+		}
+	}
+	return line
+}
+
+// splitYAMLKey は "key: value" をキーと値に分割する（クォート内のコロンは無視）。
+func splitYAMLKey(text string) (string, string, bool) {
+	var quote byte
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		switch {
+		case quote != 0:
+			if c == '\\' && quote == '"' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"':
+			quote = c
+		case c == ':' && (i == len(text)-1 || text[i+1] == ' '):
+			key := strings.TrimSpace(text[:i])
+			if key == "" {
+				return "", "", false
+			}
+			return key, strings.TrimSpace(text[i+1:]), true
+		}
+	}
+	return "", "", false
+}
+
+// splitYAMLFlow はフロー形式の要素をクォートを考慮してカンマで分割する。
+func splitYAMLFlow(text string) []string {
+	var parts []string
+	var quote byte
+	start := 0
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		switch {
+		case quote != 0:
+			if c == '\\' && quote == '"' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"':
+			quote = c
+		case c == ',':
+			parts = append(parts, text[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, text[start:])
+}
+
+// parseYAMLScalar はプレーン/シングルクォート/ダブルクォートのスカラーを解釈する。
+func parseYAMLScalar(text string) (string, error) {
+	text = strings.TrimSpace(text)
+	switch {
+	case strings.HasPrefix(text, "'"):
+		if len(text) < 2 || !strings.HasSuffix(text, "'") {
+			return "", fmt.Errorf("シングルクォートが閉じられていません: %s", text)
+		}
+		return strings.ReplaceAll(text[1:len(text)-1], "''", "'"), nil
+	case strings.HasPrefix(text, `"`):
+		var value string
+		if err := json.Unmarshal([]byte(text), &value); err != nil {
+			return "", fmt.Errorf("ダブルクォート文字列が不正です: %s", text)
+		}
+		return value, nil
+	}
+	return text, nil
+}
@@ -0,0 +1,290 @@
+package ffr
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// useSettings はテスト中だけ変換設定を差し替える。
+func useSettings(t *testing.T, s *Settings) {
+	t.Helper()
+	previous := settings
+	settings = s
+	t.Cleanup(func() { settings = previous })
+}
+
+func TestLoadSettings(t *testing.T) {
+	tests := []struct {
+		name     string
+		filename string
+		content  string
+		expected *Settings
+	}{
+		{
+			name:     "YAML with all settings",
+			filename: ".ffr.yaml",
+			content: `# プロジェクト設定
+suffixes:
+  - .blade.php
+  - .blade.html
+csrf: "@csrf"
+value_format: '{!! %s !!}'
+old_input: true
+month_format: 'M'
+month_locale: ja
+attribute_order:
+  input: [id, class, placeholder]   # id を先頭に
+  form:
+    - id
+    - class
+`,
+			expected: &Settings{
+				Suffixes:    []string{".blade.php", ".blade.html"},
+				CSRFField:   "@csrf",
+				ValueFormat: "{!! %s !!}",
+				MethodField: "directive",
+				AttributeOrder: map[string][]string{
+					"input": {"id", "class", "placeholder"},
+					"form":  {"id", "class"},
+				},
+				OldInput:    true,
+				MonthFormat: "M",
+				MonthLocale: "ja",
+			},
+		},
+		{
+			name:     "JSON",
+			filename: ".ffr.json",
+			content: `{
+  "csrf": "@csrf",
+  "old_input": false,
+  "method_field": "input",
+  "attribute_order": {"textarea": ["class", "rows"]}
+}`,
+			expected: &Settings{
+				Suffixes:       []string{".blade.php"},
+				CSRFField:      "@csrf",
+				ValueFormat:    "{{ %s }}",
+				MethodField:    "input",
+				MonthFormat:    "F",
+				AttributeOrder: map[string][]string{"textarea": {"class", "rows"}},
+			},
+		},
+		{
+			name:     "Empty YAML uses defaults",
+			filename: ".ffr.yml",
+			content:  "# nothing\n",
+			expected: defaultSettings(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), tt.filename)
+			if err := os.WriteFile(path, []byte(tt.content), 0644); err != nil {
+				t.Fatalf("Failed to write config: %v", err)
+			}
+			result, err := loadSettings(path)
+			if err != nil {
+				t.Fatalf("loadSettings() error: %v", err)
+			}
+			if !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("loadSettings() = %+v, want %+v", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestLoadSettingsErrorsReportLineNumbers(t *testing.T) {
+	tests := []struct {
+		name     string
+		filename string
+		content  string
+		expected string
+	}{
+		{
+			name:     "Unknown key",
+			filename: ".ffr.yaml",
+			content:  "csrf: '@csrf'\n\nunknown_option: 1\n",
+			expected: ".ffr.yaml:3: 不明な設定項目です: unknown_option",
+		},
+		{
+			name:     "Invalid value format",
+			filename: ".ffr.yaml",
+			content:  "suffixes: [.blade.php]\nvalue_format: '{{ value }}'\n",
+			expected: ".ffr.yaml:2: value_format には %s をちょうど1つ含めてください",
+		},
+		{
+			name:     "Invalid facade alias",
+			filename: ".ffr.yaml",
+			content:  "facade_aliases:\n  - Html\n  - 'App\\Form::'\n",
+			expected: ".ffr.yaml:3: facade_aliases の要素 \"App\\\\Form::\" はクラス名として正しくありません",
+		},
+		{
+			name:     "Invalid method_field",
+			filename: ".ffr.yaml",
+			content:  "csrf: '@csrf'\nmethod_field: hidden\n",
+			expected: ".ffr.yaml:2: method_field には directive か input を指定してください",
+		},
+		{
+			name:     "Invalid old_input",
+			filename: ".ffr.yaml",
+			content:  "old_input: yes\n",
+			expected: ".ffr.yaml:1: old_input には true か false を指定してください",
+		},
+		{
+			name:     "Empty month_format",
+			filename: ".ffr.yaml",
+			content:  "csrf: '@csrf'\nmonth_format: ''\n",
+			expected: ".ffr.yaml:2: month_format は空にできません",
+		},
+		{
+			name:     "Invalid month_locale",
+			filename: ".ffr.yaml",
+			content:  "month_locale: 'ja; drop'\n",
+			expected: ".ffr.yaml:1: month_locale にはロケール名（ja、en_US 等）を指定してください",
+		},
+		{
+			name:     "Unknown attribute_order element",
+			filename: ".ffr.yaml",
+			content:  "attribute_order:\n  input: [id]\n  marquee: [id]\n",
+			expected: ".ffr.yaml:3: attribute_order の要素名 \"marquee\" は不明です",
+		},
+		{
+			name:     "Duplicate attribute in order",
+			filename: ".ffr.yaml",
+			content:  "attribute_order:\n  input:\n    - id\n    - id\n",
+			expected: ".ffr.yaml:4: input の要素 \"id\" が重複しています",
+		},
+		{
+			name:     "Tab indentation",
+			filename: ".ffr.yaml",
+			content:  "suffixes:\n\t- .blade.php\n",
+			expected: ".ffr.yaml:2: インデントにタブは使用できません",
+		},
+		{
+			name:     "Duplicate key",
+			filename: ".ffr.yaml",
+			content:  "csrf: a\ncsrf: b\n",
+			expected: ".ffr.yaml:2: キー \"csrf\" が重複しています（1 行目）",
+		},
+		{
+			name:     "Wrong type in JSON",
+			filename: ".ffr.json",
+			content:  "{\n  \"suffixes\": \".blade.php\"\n}\n",
+			expected: ".ffr.json:2: suffixes には文字列のリストを指定してください",
+		},
+		{
+			name:     "Empty csrf in JSON",
+			filename: ".ffr.json",
+			content:  "{\n  \"csrf\":\n    \"\"\n}\n",
+			expected: ".ffr.json:3: csrf は空にできません",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			path := filepath.Join(dir, tt.filename)
+			if err := os.WriteFile(path, []byte(tt.content), 0644); err != nil {
+				t.Fatalf("Failed to write config: %v", err)
+			}
+			_, err := loadSettings(path)
+			if err == nil {
+				t.Fatal("loadSettings() should fail")
+			}
+			message := strings.TrimPrefix(err.Error(), dir+string(filepath.Separator))
+			if !strings.HasPrefix(message, tt.expected) {
+				t.Errorf("error = %q, want prefix %q", message, tt.expected)
+			}
+		})
+	}
+}
+
+func TestFindConfigFile(t *testing.T) {
+	root := t.TempDir()
+	viewsDir := filepath.Join(root, "resources", "views", "users")
+	if err := os.MkdirAll(viewsDir, 0755); err != nil {
+		t.Fatalf("Failed to create dirs: %v", err)
+	}
+	configPath := filepath.Join(root, ".ffr.yaml")
+	if err := os.WriteFile(configPath, []byte("csrf: '@csrf'\n"), 0644); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+	viewFile := filepath.Join(viewsDir, "edit.blade.php")
+	if err := os.WriteFile(viewFile, []byte(""), 0644); err != nil {
+		t.Fatalf("Failed to write view: %v", err)
+	}
+
+	for _, start := range []string{viewsDir, viewFile, root} {
+		if result := findConfigFile(start); result != configPath {
+			t.Errorf("findConfigFile(%s) = %q, want %q", start, result, configPath)
+		}
+	}
+}
+
+func TestSettingsAffectConversion(t *testing.T) {
+	useSettings(t, &Settings{
+		Suffixes:    []string{".blade.php"},
+		CSRFField:   "@csrf",
+		ValueFormat: "{!! %s !!}",
+		AttributeOrder: map[string][]string{
+			"input": {"id", "class", "placeholder"},
+		},
+	})
+
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:  "CSRF helper",
+			input: `{!! Form::open(['route' => 'user.store', 'method' => 'POST']) !!}`,
+			expected: `<form action="{{ route('user.store') }}" method="POST">
+@csrf`,
+		},
+		{
+			name:     "Value format and attribute order",
+			input:    `{{ Form::text('name', $user->name, ['placeholder' => 'Name', 'class' => 'form-control', 'id' => 'name']) }}`,
+			expected: `<input type="text" name="name" value="{!! $user->name !!}" id="name" class="form-control" placeholder="Name">`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := convertFormPatterns(tt.input)
+			if result != tt.expected {
+				t.Errorf("convertFormPatterns() = %q, want %q", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestRunUsesDiscoveredConfigFile(t *testing.T) {
+	useSettings(t, defaultSettings())
+	root := t.TempDir()
+	if err := os.WriteFile(filepath.Join(root, ".ffr.yaml"), []byte("suffixes: [.tpl]\ncsrf: '@csrf'\n"), 0644); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+	view := filepath.Join(root, "form.tpl")
+	if err := os.WriteFile(view, []byte(`{{ Form::open(['method' => 'POST']) }}`), 0644); err != nil {
+		t.Fatalf("Failed to write view: %v", err)
+	}
+
+	if code := Run([]string{"form-facade-replacer", root}); code != 0 {
+		t.Fatalf("Run() = %d, want 0", code)
+	}
+	content, _ := os.ReadFile(view)
+	if string(content) != "<form action=\"\" method=\"POST\">\n@csrf" {
+		t.Errorf("unexpected conversion:\n%s", string(content))
+	}
+
+	// 不正な設定ファイルはエラーになる
+	if code := Run([]string{"form-facade-replacer", "--config", filepath.Join(root, "missing.yaml"), root}); code != 1 {
+		t.Errorf("Run() with missing config = %d, want 1", code)
+	}
+}
@@ -0,0 +1,246 @@
+// diff.go: dry-run 用の統一差分（unified diff）生成ロジック。
+package ffr
+
+import (
+	"fmt"
+	"strings"
+)
+
+// 差分の既定コンテキスト行数（diff -u と同じ）
+const defaultDiffContext = 3
+
+// diffOp は行単位の編集操作（' ' 一致 / '-' 削除 / '+' 追加）を表す。
+type diffOp struct {
+	Kind byte
+	Line string
+}
+
+// splitLines はテキストを改行を保持したまま行に分割する。
+func splitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	lines := strings.SplitAfter(text, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// diffLines は Myers のアルゴリズムで a から b への最短編集列を求める。
+func diffLines(a, b []string) []diffOp {
+	// 共通の先頭・末尾を除外して探索範囲を縮める
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	var ops []diffOp
+	for _, line := range a[:prefix] {
+		ops = append(ops, diffOp{Kind: ' ', Line: line})
+	}
+	ops = append(ops, myersDiff(a[prefix:len(a)-suffix], b[prefix:len(b)-suffix])...)
+	for _, line := range a[len(a)-suffix:] {
+		ops = append(ops, diffOp{Kind: ' ', Line: line})
+	}
+	return ops
+}
+
+// myersDiff は Myers の O(ND) 差分アルゴリズムの本体。
+func myersDiff(a, b []string) []diffOp {
+	n, m := len(a), len(b)
+	if n == 0 && m == 0 {
+		return nil
+	}
+	max := n + m
+	offset := max + 1
+	v := make([]int, 2*max+3)
+	// trace[d] には d 回目の探索開始時点の v[-d..d] を保存する
+	var trace [][]int
+
+search:
+	for d := 0; d <= max; d++ {
+		snapshot := make([]int, 2*d+1)
+		copy(snapshot, v[offset-d:offset+d+1])
+		trace = append(trace, snapshot)
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+				x = v[offset+k+1]
+			} else {
+				x = v[offset+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			v[offset+k] = x
+			if x >= n && y >= m {
+				break search
+			}
+		}
+	}
+
+	// 終点から逆順にたどって編集列を復元する
+	var reversed []diffOp
+	x, y := n, m
+	for d := len(trace) - 1; d >= 0; d-- {
+		vd := trace[d]
+		at := func(k int) int { return vd[k+d] }
+		k := x - y
+		var prevK int
+		if k == -d || (k != d && at(k-1) < at(k+1)) {
+			prevK = k + 1
+		} else {
+			prevK = k - 1
+		}
+		prevX := 0
+		if d > 0 {
+			prevX = at(prevK)
+		}
+		prevY := prevX - prevK
+		for x > prevX && y > prevY {
+			reversed = append(reversed, diffOp{Kind: ' ', Line: a[x-1]})
+			x--
+			y--
+		}
+		if d > 0 {
+			if x == prevX {
+				reversed = append(reversed, diffOp{Kind: '+', Line: b[prevY]})
+			} else {
+				reversed = append(reversed, diffOp{Kind: '-', Line: a[prevX]})
+			}
+		}
+		x, y = prevX, prevY
+	}
+
+	ops := make([]diffOp, len(reversed))
+	for i, op := range reversed {
+		ops[len(reversed)-1-i] = op
+	}
+	return ops
+}
+
+// unifiedDiff は path の変更前後テキストから git apply 互換の統一差分を生成する。
+// 差分がない場合は空文字を返す。
+func unifiedDiff(path, oldText, newText string, context int) string {
+	if oldText == newText {
+		return ""
+	}
+	if context < 0 {
+		context = defaultDiffContext
+	}
+	ops := diffLines(splitLines(oldText), splitLines(newText))
+
+	var out strings.Builder
+	fmt.Fprintf(&out, "diff --git a/%s b/%s\n", path, path)
+	fmt.Fprintf(&out, "--- a/%s\n", path)
+	fmt.Fprintf(&out, "+++ b/%s\n", path)
+
+	// 変更行の位置を求め、コンテキストが重なるものを1つのハンクにまとめる
+	var changes []int
+	for i, op := range ops {
+		if op.Kind != ' ' {
+			changes = append(changes, i)
+		}
+	}
+	for i := 0; i < len(changes); {
+		start := changes[i] - context
+		if start < 0 {
+			start = 0
+		}
+		j := i
+		for j+1 < len(changes) && changes[j+1]-changes[j] <= 2*context+1 {
+			j++
+		}
+		end := changes[j] + context + 1
+		if end > len(ops) {
+			end = len(ops)
+		}
+		writeHunk(&out, ops, start, end)
+		i = j + 1
+	}
+	return out.String()
+}
+
+// writeHunk は ops[start:end] を1つのハンクとして出力する。
+func writeHunk(out *strings.Builder, ops []diffOp, start, end int) {
+	oldLine, newLine := 1, 1
+	for _, op := range ops[:start] {
+		if op.Kind != '+' {
+			oldLine++
+		}
+		if op.Kind != '-' {
+			newLine++
+		}
+	}
+	oldCount, newCount := 0, 0
+	for _, op := range ops[start:end] {
+		if op.Kind != '+' {
+			oldCount++
+		}
+		if op.Kind != '-' {
+			newCount++
+		}
+	}
+	fmt.Fprintf(out, "@@ -%s +%s @@\n", hunkRange(oldLine, oldCount), hunkRange(newLine, newCount))
+	for _, op := range ops[start:end] {
+		out.WriteByte(op.Kind)
+		out.WriteString(op.Line)
+		if !strings.HasSuffix(op.Line, "\n") {
+			out.WriteString("\n\\ No newline at end of file\n")
+		}
+	}
+}
+
+// hunkRange はハンクヘッダの範囲表記（start,count）を返す。
+func hunkRange(start, count int) string {
+	switch count {
+	case 0:
+		// 空範囲は直前の行番号で表す（GNU diff の慣例）
+		return fmt.Sprintf("%d,0", start-1)
+	case 1:
+		return fmt.Sprintf("%d", start)
+	default:
+		return fmt.Sprintf("%d,%d", start, count)
+	}
+}
+
+// ANSI カラーコード
+const (
+	ansiReset = "\x1b[0m"
+	ansiBold  = "\x1b[1m"
+	ansiRed   = "\x1b[31m"
+	ansiGreen = "\x1b[32m"
+	ansiCyan  = "\x1b[36m"
+)
+
+// colorizeDiff は統一差分にターミナル向けの色を付ける。
+func colorizeDiff(diff string) string {
+	var out strings.Builder
+	for _, line := range splitLines(diff) {
+		body := strings.TrimSuffix(line, "\n")
+		color := ""
+		switch {
+		case strings.HasPrefix(body, "diff --git"), strings.HasPrefix(body, "--- "), strings.HasPrefix(body, "+++ "):
+			color = ansiBold
+		case strings.HasPrefix(body, "@@"):
+			color = ansiCyan
+		case strings.HasPrefix(body, "-"):
+			color = ansiRed
+		case strings.HasPrefix(body, "+"):
+			color = ansiGreen
+		}
+		if color == "" {
+			out.WriteString(line)
+			continue
+		}
+		out.WriteString(color + body + ansiReset + "\n")
+	}
+	return out.String()
+}
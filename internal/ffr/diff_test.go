@@ -0,0 +1,255 @@
+package ffr
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestUnifiedDiff(t *testing.T) {
+	tests := []struct {
+		name     string
+		oldText  string
+		newText  string
+		context  int
+		expected string
+	}{
+		{
+			name:     "No changes",
+			oldText:  "a\nb\n",
+			newText:  "a\nb\n",
+			context:  3,
+			expected: "",
+		},
+		{
+			name:    "Single line replaced",
+			oldText: "a\n{{ Form::text('name') }}\nc\n",
+			newText: "a\n<input type=\"text\" name=\"name\" value=\"\">\nc\n",
+			context: 3,
+			expected: `diff --git a/view.blade.php b/view.blade.php
+--- a/view.blade.php
++++ b/view.blade.php
+@@ -1,3 +1,3 @@
+ a
+-{{ Form::text('name') }}
++<input type="text" name="name" value="">
+ c
+`,
+		},
+		{
+			name:    "Line added at top with zero context",
+			oldText: "a\nb\n",
+			newText: "x\na\nb\n",
+			context: 0,
+			expected: `diff --git a/view.blade.php b/view.blade.php
+--- a/view.blade.php
++++ b/view.blade.php
+@@ -0,0 +1 @@
++x
+`,
+		},
+		{
+			name:    "Distant changes become separate hunks",
+			oldText: "1\n2\n3\n4\n5\n6\n7\n8\n",
+			newText: "1\nX\n3\n4\n5\n6\nY\n8\n",
+			context: 1,
+			expected: `diff --git a/view.blade.php b/view.blade.php
+--- a/view.blade.php
++++ b/view.blade.php
+@@ -1,3 +1,3 @@
+ 1
+-2
++X
+ 3
+@@ -6,3 +6,3 @@
+ 6
+-7
++Y
+ 8
+`,
+		},
+		{
+			name:    "Missing newline at end of file",
+			oldText: "a\nb",
+			newText: "a\nc",
+			context: 3,
+			expected: `diff --git a/view.blade.php b/view.blade.php
+--- a/view.blade.php
++++ b/view.blade.php
+@@ -1,2 +1,2 @@
+ a
+-b
+\ No newline at end of file
++c
+\ No newline at end of file
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := unifiedDiff("view.blade.php", tt.oldText, tt.newText, tt.context)
+			if result != tt.expected {
+				t.Errorf("unifiedDiff() =\n%s\nwant:\n%s", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestUnifiedDiffRoundTrip(t *testing.T) {
+	tests := []struct {
+		name    string
+		oldText string
+		newText string
+	}{
+		{"Insert and delete", "a\nb\nc\nd\ne\n", "a\nc\nd\nx\ne\ny\n"},
+		{"Everything replaced", "a\nb\n", "c\nd\ne"},
+		{"From empty", "", "a\nb\n"},
+		{"To empty", "a\nb\n", ""},
+		{"Repeated lines", "x\nx\ny\nx\nx\n", "x\ny\ny\nx\n"},
+	}
+
+	for _, tt := range tests {
+		for _, context := range []int{0, 1, 3} {
+			t.Run(tt.name+"/U"+strconv.Itoa(context), func(t *testing.T) {
+				diff := unifiedDiff("f", tt.oldText, tt.newText, context)
+				result := applyUnifiedDiff(t, tt.oldText, diff)
+				if result != tt.newText {
+					t.Errorf("round trip mismatch.\nGot:\n%q\nWant:\n%q\nDiff:\n%s", result, tt.newText, diff)
+				}
+			})
+		}
+	}
+}
+
+// applyUnifiedDiff はテスト用の簡易パッチ適用（ハンクの行番号を信頼して適用する）。
+func applyUnifiedDiff(t *testing.T, oldText, diff string) string {
+	t.Helper()
+	oldLines := splitLines(oldText)
+	var out []string
+	pos := 0
+	lines := splitLines(diff)
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		if !strings.HasPrefix(line, "@@") {
+			continue
+		}
+		header := strings.Fields(line)[1] // -start,count
+		start, _ := strconv.Atoi(strings.Split(strings.TrimPrefix(header, "-"), ",")[0])
+		count := 1
+		if parts := strings.Split(header, ","); len(parts) == 2 {
+			count, _ = strconv.Atoi(parts[1])
+		}
+		if count == 0 {
+			start++
+		}
+		out = append(out, oldLines[pos:start-1]...)
+		pos = start - 1
+		for i+1 < len(lines) && !strings.HasPrefix(lines[i+1], "@@") {
+			i++
+			body := lines[i]
+			if strings.HasPrefix(body, `\`) {
+				// 直前の行の改行を取り除く
+				if strings.HasPrefix(lines[i-1], "-") {
+					continue
+				}
+				out[len(out)-1] = strings.TrimSuffix(out[len(out)-1], "\n")
+				continue
+			}
+			switch body[0] {
+			case ' ':
+				out = append(out, oldLines[pos])
+				pos++
+			case '-':
+				pos++
+			case '+':
+				out = append(out, body[1:])
+			}
+		}
+	}
+	out = append(out, oldLines[pos:]...)
+	return strings.Join(out, "")
+}
+
+func TestDryRunDoesNotModifyFiles(t *testing.T) {
+	tempDir := t.TempDir()
+	content := "<div>\n{!! Form::text('name') !!}\n</div>\n"
+	testFile := filepath.Join(tempDir, "edit.blade.php")
+	if err := os.WriteFile(testFile, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	config := newReplacementConfig()
+	config.TargetPath = tempDir
+	config.DryRun = true
+	config.PatchFile = filepath.Join(tempDir, "out.patch")
+
+	if err := processBladeFiles(config); err != nil {
+		t.Fatalf("Failed to process directory: %v", err)
+	}
+	if err := writePatchFile(config); err != nil {
+		t.Fatalf("Failed to write patch: %v", err)
+	}
+
+	result, err := os.ReadFile(testFile)
+	if err != nil {
+		t.Fatalf("Failed to read file: %v", err)
+	}
+	if string(result) != content {
+		t.Errorf("dry-run modified the file:\n%s", string(result))
+	}
+	if len(config.Results) != 1 || !config.Results[0].Changed() {
+		t.Fatalf("expected one changed result, got %+v", config.Results)
+	}
+
+	patch, err := os.ReadFile(config.PatchFile)
+	if err != nil {
+		t.Fatalf("Failed to read patch: %v", err)
+	}
+	for _, want := range []string{
+		"-{!! Form::text('name') !!}\n",
+		"+<input type=\"text\" name=\"name\" value=\"\">\n",
+		"@@ -1,3 +1,3 @@\n",
+	} {
+		if !strings.Contains(string(patch), want) {
+			t.Errorf("patch does not contain %q:\n%s", want, string(patch))
+		}
+	}
+}
+
+func TestDryRunSummaryReportsOriginalLines(t *testing.T) {
+	original := "{{ Form::select('size', ['L' => 'Large', 'S' => 'Small']) }}\n<p>{{ Form::customMacro('x') }}</p>\n"
+	converted, warnings, sources := convertTemplateMapped(original)
+	results := []*FileResult{{Path: "a.blade.php", Original: original, Converted: converted, Warnings: warnings, Sources: sources}}
+
+	var out strings.Builder
+	printRemainingInResults(&out, results)
+	// 前の select が複数行に展開されても、元のファイルの行番号を表示する
+	expected := "=== 残存するForm facadeパターン ===\na.blade.php:2:<p>{{ Form::customMacro('x') }}</p>\n"
+	if !strings.HasSuffix(out.String(), expected) {
+		t.Errorf("printRemainingInResults() =\n%s\nwant suffix:\n%s", out.String(), expected)
+	}
+}
+
+func TestParseArgsDryRunOptions(t *testing.T) {
+	config := newReplacementConfig()
+	action, err := parseArgs([]string{"--diff", "-U5", "--color", "--patch=out.patch", "views"}, config)
+	if err != nil {
+		t.Fatalf("parseArgs() error: %v", err)
+	}
+	if action != actionRun {
+		t.Errorf("action = %v, want actionRun", action)
+	}
+	if !config.DryRun || !config.ColorDiff || config.DiffContext != 5 || config.PatchFile != "out.patch" || config.TargetPath != "views" {
+		t.Errorf("unexpected config: %+v", config)
+	}
+
+	if _, err := parseArgs([]string{"--diff-context", "-1", "views"}, newReplacementConfig()); err == nil {
+		t.Error("expected error for negative context")
+	}
+	if _, err := parseArgs([]string{"--unknown", "views"}, newReplacementConfig()); err == nil {
+		t.Error("expected error for unknown option")
+	}
+}
@@ -0,0 +1,153 @@
+// echo_expression.go: 三項演算子・文字列連結の中に Form:: 呼び出しを含むエコーを Blade に書き換える。
+package ffr
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// echoRewriter はエコーの式を Form:: 呼び出しごとに分解して Blade に組み立て直す。
+type echoRewriter struct {
+	ctx            *formContext
+	opener, closer string // 元のエコーのタグ（Form:: 以外の部分はこのタグで出力する）
+	accept         func(method string) bool
+	ignored        bool          // 対象外・未対応のメソッドを含む（何もせず残す）
+	converted      []*phpNode    // 変換できた Form:: 呼び出し
+	problems       []callProblem // 変換できなかった Form:: 呼び出し（offset は式のソース内の位置）
+}
+
+// rewriteEchoExpression はエコーの中身 body を解析し、Form:: 呼び出しを HTML に、
+// 三項演算子を @if/@else/@endif に、文字列連結を連続したタグに書き換える。
+// 書き換えられない呼び出しがあれば problems を返し、対象外のメソッドを含む場合や
+// Form:: 呼び出しがない場合は空文字を返す。
+func rewriteEchoExpression(ctx *formContext, body, opener, closer string, accept func(method string) bool) (string, []callProblem, error) {
+	node, err := parsePHPExpr(body)
+	if err != nil {
+		return "", nil, err
+	}
+	r := &echoRewriter{ctx: ctx, opener: opener, closer: closer, accept: accept}
+	html := r.rewrite(node)
+	if r.ignored || len(r.converted)+len(r.problems) == 0 {
+		return "", nil, nil
+	}
+	if len(r.problems) > 0 {
+		for _, call := range r.converted {
+			r.problem(call, "同じエコー内に変換できない Form:: 呼び出しがあるため変換しません")
+		}
+		sort.SliceStable(r.problems, func(i, j int) bool { return r.problems[i].offset < r.problems[j].offset })
+		return "", r.problems, nil
+	}
+	return html, nil, nil
+}
+
+// rewrite は式 n を Blade のテンプレート断片にする。
+func (r *echoRewriter) rewrite(n *phpNode) string {
+	switch {
+	case n.Kind == phpParen && n.Value == "":
+		return r.rewrite(n.Children[0])
+	case facadeMethod(n) != "":
+		return r.formCall(n)
+	case n.Kind == phpBinary && n.Value == "." && containsFacadeCall(n):
+		return r.rewrite(n.Children[0]) + r.rewrite(n.Children[1])
+	case n.Kind == phpTernary && n.Children[1] != nil && !containsFacadeCall(n.Children[0]) && containsFacadeCall(n):
+		then, otherwise := r.rewrite(n.Children[1]), r.rewrite(n.Children[2])
+		if otherwise == "" {
+			return fmt.Sprintf("@if(%s) %s @endif", n.Children[0].Src, then)
+		}
+		return fmt.Sprintf("@if(%s) %s @else %s @endif", n.Children[0].Src, then, otherwise)
+	case containsFacadeCall(n):
+		// 関数の引数・条件式・?? など、結果を HTML のまま出力できない位置にある
+		walkPHPNode(n, func(c *phpNode) {
+			if facadeMethod(c) != "" {
+				r.problem(c, "関数の引数や条件式など、式の内側にあるため変換できません")
+			}
+		})
+		return ""
+	case isEmptyLiteral(n):
+		return ""
+	}
+	return fmt.Sprintf("%s %s %s", r.opener, n.Src, r.closer)
+}
+
+// formCall は Form::メソッド(...) のノードをハンドラで HTML にする。
+func (r *echoRewriter) formCall(n *phpNode) string {
+	method := facadeMethod(n)
+	handler := formHandlers[strings.ToLower(method)]
+	if handler == nil || !r.accept(strings.ToLower(method)) {
+		r.ignored = true
+		return ""
+	}
+	callee := n.Children[0]
+	args := strings.TrimSpace(n.Src[len(callee.Src):])
+	args = strings.TrimSpace(args[1 : len(args)-1])
+	html, ok := callHandler(r.ctx, strings.ToLower(method), handler, args)
+	if !ok {
+		r.problem(n, "引数の形式に対応していないため変換できません")
+		return ""
+	}
+	r.converted = append(r.converted, n)
+	return html
+}
+
+// problem は Form:: 呼び出し n を変換できなかったものとして記録する。
+func (r *echoRewriter) problem(n *phpNode, message string) {
+	r.problems = append(r.problems, callProblem{offset: n.Pos, method: facadeMethod(n), message: message})
+}
+
+// facadeMethod は n が Form::メソッド(...) の呼び出しならメソッド名を返す。
+func facadeMethod(n *phpNode) string {
+	if n == nil || n.Kind != phpCall || n.Value == "new" {
+		return ""
+	}
+	callee := n.Children[0]
+	if callee.Kind != phpProperty || callee.Value != "::" || !isFacadeName(callee.Children[0]) {
+		return ""
+	}
+	if name := callee.Name; name != "" && isPHPNameStart(name[0]) {
+		return name
+	}
+	return ""
+}
+
+// isFacadeName は n が Form ファサード（別名・完全修飾名を含む）のクラス名かを返す。
+func isFacadeName(n *phpNode) bool {
+	return n.Kind == phpConst && isFormFacadeName(n.Value)
+}
+
+// containsFacadeCall は n の中に Form:: 呼び出しがあるかを返す。
+func containsFacadeCall(n *phpNode) bool {
+	found := false
+	walkPHPNode(n, func(c *phpNode) {
+		if facadeMethod(c) != "" {
+			found = true
+		}
+	})
+	return found
+}
+
+// isEmptyLiteral は n が何も出力しない値（” / "" / null）かを返す。
+func isEmptyLiteral(n *phpNode) bool {
+	if value, ok := n.stringLiteral(); ok {
+		return value == ""
+	}
+	return n.isConst("null")
+}
+
+// walkPHPNode は n とその子孫のノードを順に visit に渡す。
+func walkPHPNode(n *phpNode, visit func(*phpNode)) {
+	if n == nil {
+		return
+	}
+	visit(n)
+	for _, c := range n.Children {
+		walkPHPNode(c, visit)
+	}
+	for _, item := range n.Items {
+		walkPHPNode(item.Key, visit)
+		walkPHPNode(item.Value, visit)
+	}
+	for _, arg := range n.Args {
+		walkPHPNode(arg.Value, visit)
+	}
+}
@@ -0,0 +1,82 @@
+package ffr
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestConvertTemplateRewritesEchoExpressions(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+		warnings []conversionWarning
+	}{
+		{
+			name:     "Ternary with a fallback expression",
+			input:    `{!! $editable ? Form::text('name', $v) : e($v) !!}`,
+			expected: `@if($editable) <input type="text" name="name" value="{{ $v }}"> @else {!! e($v) !!} @endif`,
+		},
+		{
+			name:     "Ternary with an empty branch",
+			input:    `{{ $errors->any() ? Form::hidden('retry', 1) : '' }}`,
+			expected: `@if($errors->any()) <input type="hidden" name="retry" value="{{ 1 }}"> @endif`,
+		},
+		{
+			name:     "Concatenation becomes consecutive tags",
+			input:    `{!! Form::label('a') . Form::text('a') !!}`,
+			expected: `<label for="a">{!! 'a' !!}</label><input type="text" name="a" value="">`,
+		},
+		{
+			name:     "Concatenation with other operands keeps them as echoes",
+			input:    `{{ '<p>' . $prefix . Form::text('a') }}`,
+			expected: `{{ '<p>' . $prefix }}<input type="text" name="a" value="">`,
+		},
+		{
+			name:     "Nested ternary in parentheses",
+			input:    `{!! $a ? ($b ? Form::text('x') : Form::email('x')) : '' !!}`,
+			expected: `@if($a) @if($b) <input type="text" name="x" value=""> @else <input type="email" name="x" value=""> @endif @endif`,
+		},
+		{
+			name:     "Call inside a function argument is reported",
+			input:    "{{ strtoupper(Form::text('a')) }}",
+			expected: "{{ strtoupper(Form::text('a')) }}",
+			warnings: []conversionWarning{
+				{Line: 1, Column: 15, Method: "text", Message: "関数の引数や条件式など、式の内側にあるため変換できません"},
+			},
+		},
+		{
+			name:     "Call in a condition blocks the whole echo",
+			input:    "{!! Form::old('a') ? Form::text('a') : '' !!}",
+			expected: "{!! Form::old('a') ? Form::text('a') : '' !!}",
+		},
+		{
+			name:     "Other calls in the same echo are reported too",
+			input:    "{!! Form::label('a') . Form::select('a') !!}",
+			expected: "{!! Form::label('a') . Form::select('a') !!}",
+			warnings: []conversionWarning{
+				{Line: 1, Column: 5, Method: "label", Message: "同じエコー内に変換できない Form:: 呼び出しがあるため変換しません"},
+				{Line: 1, Column: 24, Method: "select", Message: "引数の形式に対応していないため変換できません"},
+			},
+		},
+		{
+			name:     "Unparsable expression",
+			input:    "{{ $a ? Form::text('a') }}",
+			expected: "{{ $a ? Form::text('a') }}",
+			warnings: []conversionWarning{
+				{Line: 1, Column: 9, Method: "text", Message: "式を解析できないため変換できません"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, warnings := convertTemplate(tt.input)
+			if result != tt.expected {
+				t.Errorf("convertTemplate() =\n%s\nwant:\n%s", result, tt.expected)
+			}
+			if !reflect.DeepEqual(warnings, tt.warnings) {
+				t.Errorf("warnings =\n%+v\nwant:\n%+v", warnings, tt.warnings)
+			}
+		})
+	}
+}
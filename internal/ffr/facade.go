@@ -0,0 +1,66 @@
+// facade.go: Form ファサードの参照（Form:: / \Form:: / 完全修飾名 / 設定した別名）の検出。
+package ffr
+
+import "strings"
+
+// builtinFacadeNames は設定によらず Form ファサードとして扱うクラス名（先頭の \ は任意）
+var builtinFacadeNames = []string{"Form", `Collective\Html\FormFacade`}
+
+// isFormFacadeName は name が Form ファサードのクラス名か、設定した別名かを返す。
+// PHP のクラス名と同じく大文字・小文字は区別しない。
+func isFormFacadeName(name string) bool {
+	name = strings.TrimPrefix(name, `\`)
+	for _, names := range [][]string{builtinFacadeNames, settings.FacadeAliases} {
+		for _, candidate := range names {
+			if strings.EqualFold(strings.TrimPrefix(candidate, `\`), name) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// isValidFacadeName は name が名前空間付きのクラス名（Foo / \App\Foo）として正しいかを返す。
+func isValidFacadeName(name string) bool {
+	for _, part := range strings.Split(strings.TrimPrefix(name, `\`), `\`) {
+		if part == "" || !isPHPNameStart(part[0]) || scanPHPName(part, 0) != len(part) {
+			return false
+		}
+	}
+	return true
+}
+
+// facadeRef はテキスト上のファサードの参照 text[start:end]（"\Form::" のように :: まで含む）。
+type facadeRef struct {
+	start int
+	end   int
+}
+
+// findFacadeRefs は text[from:to] に現れる Form ファサードの参照をすべて返す。
+func findFacadeRefs(text string, from, to int) []facadeRef {
+	var refs []facadeRef
+	for pos := from; ; {
+		idx := strings.Index(text[pos:to], "::")
+		if idx < 0 {
+			return refs
+		}
+		colons := pos + idx
+		pos = colons + len("::")
+		start := colons
+		for start > from && (isWordByte(text[start-1]) || text[start-1] == '\\') {
+			start--
+		}
+		// $form:: や $this->Form:: はファサードではない
+		if start > 0 && (text[start-1] == '$' || text[start-1] == '>') {
+			continue
+		}
+		if start < colons && isFormFacadeName(text[start:colons]) {
+			refs = append(refs, facadeRef{start, pos})
+		}
+	}
+}
+
+// containsFacadeRef は s に Form ファサードの参照が含まれるかを返す。
+func containsFacadeRef(s string) bool {
+	return strings.Contains(s, "::") && len(findFacadeRefs(s, 0, len(s))) > 0
+}
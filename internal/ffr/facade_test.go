@@ -0,0 +1,103 @@
+package ffr
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFindFacadeRefs(t *testing.T) {
+	useSettings(t, &Settings{FacadeAliases: []string{`App\Support\Html`}})
+	text := `Form::a \Form::b \Collective\Html\FormFacade::c App\Support\Html::d MyForm::e $form::f App\Form::g form::h`
+	var got []string
+	for _, ref := range findFacadeRefs(text, 0, len(text)) {
+		got = append(got, text[ref.start:ref.end]+leadingIdentifier(text[ref.end:]))
+	}
+	expected := []string{`Form::a`, `\Form::b`, `\Collective\Html\FormFacade::c`, `App\Support\Html::d`, `form::h`}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("findFacadeRefs() = %v, want %v", got, expected)
+	}
+}
+
+func TestConvertTemplatePHPTagsAndFacadeNames(t *testing.T) {
+	useSettings(t, func() *Settings {
+		s := defaultSettings()
+		s.FacadeAliases = []string{"Html"}
+		return s
+	}())
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+		warnings []conversionWarning
+	}{
+		{
+			name:     "PHP echo statement",
+			input:    `<?php echo Form::text('a'); ?>`,
+			expected: `<input type="text" name="a" value="">`,
+		},
+		{
+			name:     "Short echo tag",
+			input:    `<?= Form::email('a') ?>`,
+			expected: `<input type="email" name="a" value="">`,
+		},
+		{
+			name:     "Echo inside @php",
+			input:    "@php\n    echo Form::hidden('h', $v);\n@endphp",
+			expected: `<input type="hidden" name="h" value="{{ $v }}">`,
+		},
+		{
+			name:     "Other operands of a PHP echo stay unescaped",
+			input:    `<?= '<p>' . Form::text('a') ?>`,
+			expected: `{!! '<p>' !!}<input type="text" name="a" value="">`,
+		},
+		{
+			name:     "Fully-qualified facade names",
+			input:    `{!! \Form::text('a') !!}{!! \Collective\Html\FormFacade::label('a') !!}`,
+			expected: `<input type="text" name="a" value=""><label for="a">{!! 'a' !!}</label>`,
+		},
+		{
+			name:     "Configured alias",
+			input:    `{!! Html::text('a') . Html::close() !!}`,
+			expected: `<input type="text" name="a" value=""></form>`,
+		},
+		{
+			name:     "PHP blocks with several statements are skipped",
+			input:    `<?php $x = 1; echo Form::text('a'); ?>`,
+			expected: `<?php $x = 1; echo Form::text('a'); ?>`,
+			warnings: []conversionWarning{
+				{Line: 1, Column: 20, Method: "text", Message: "PHP ブロック内のため変換しません", Skipped: true},
+			},
+		},
+		{
+			name:     "Static calls on other classes are ignored",
+			input:    `{!! MyForm::text('a') !!}{{ $form::text('b') }}`,
+			expected: `{!! MyForm::text('a') !!}{{ $form::text('b') }}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, warnings := convertTemplate(tt.input)
+			if result != tt.expected {
+				t.Errorf("convertTemplate() =\n%s\nwant:\n%s", result, tt.expected)
+			}
+			if !reflect.DeepEqual(warnings, tt.warnings) {
+				t.Errorf("warnings =\n%+v\nwant:\n%+v", warnings, tt.warnings)
+			}
+		})
+	}
+}
+
+func TestRemainingOccurrencesIncludeFacadeAliases(t *testing.T) {
+	useSettings(t, &Settings{FacadeAliases: []string{"Html"}})
+	text := "<p>{!! \\Form::macro('x') !!}</p>\n{{ Html::foo() }}\n{{ MyForm::bar() }}"
+	var got []string
+	for _, occ := range findFormFacadeOccurrences(text) {
+		got = append(got, occ.Method)
+	}
+	if !reflect.DeepEqual(got, []string{"macro", "foo"}) {
+		t.Errorf("methods = %v, want [macro foo]", got)
+	}
+	if counts := countFormMethods(text); !reflect.DeepEqual(counts, map[string]int{"macro": 1, "foo": 1}) {
+		t.Errorf("countFormMethods() = %v", counts)
+	}
+}
@@ -3,7 +3,7 @@ package ffr
 
 import (
 	"fmt"
-	"os"
+	"io"
 	"strings"
 )
 
@@ -16,30 +16,61 @@ var (
 
 // --- Meta (usage/version) ---
 // printUsage は CLI の使用方法を表示する（internal/ffr/cli.go から利用）。
-func printUsage() {
-	fmt.Println("Laravel Form Facade から HTMLタグ置換スクリプト")
-	fmt.Println("使用方法: go run form_facade_replacer.go <ファイルパス|ディレクトリパス>")
-	fmt.Println()
-	fmt.Println("引数:")
-	fmt.Println(" ファイルパス 対象の.blade.phpファイル")
-	fmt.Println(" ディレクトリパス 対象ディレクトリ（配下の.blade.phpファイルを再帰処理）")
-	fmt.Println()
-	fmt.Println("オプション:")
-	fmt.Println(" -h, --help このヘルプメッセージを表示")
-	fmt.Println(" -v, --version バージョン情報を表示")
-	fmt.Println()
-	fmt.Println("例:")
-	fmt.Println(" go run form_facade_replacer.go resources/views/hoge")
-	fmt.Println(" go run form_facade_replacer.go resources/views/hoge/fuga.blade.php")
+func printUsage(w io.Writer) {
+	fmt.Fprintln(w, "Laravel Form Facade から HTMLタグ置換スクリプト")
+	fmt.Fprintln(w, "使用方法: go run form_facade_replacer.go [オプション] <ファイルパス|ディレクトリパス|->")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "引数:")
+	fmt.Fprintln(w, " ファイルパス 対象の.blade.phpファイル")
+	fmt.Fprintln(w, " ディレクトリパス 対象ディレクトリ（配下の.blade.phpファイルを再帰処理）")
+	fmt.Fprintln(w, " - 標準入力のテンプレートを変換して標準出力へ書き出す（--stdin と同じ）")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "オプション:")
+	fmt.Fprintln(w, " -h, --help このヘルプメッセージを表示")
+	fmt.Fprintln(w, " -v, --version バージョン情報を表示")
+	fmt.Fprintln(w, " --dry-run, --diff ファイルを書き換えず、変換結果を統一差分で表示")
+	fmt.Fprintln(w, " -U, --diff-context <行数> 差分のコンテキスト行数（既定: 3）")
+	fmt.Fprintln(w, " --color 差分を色付きで表示")
+	fmt.Fprintln(w, " --patch <ファイル> git apply 互換のパッチを書き出す（dry-run を含む）")
+	fmt.Fprintln(w, " --check ファイルを書き換えずに検査し、終了コードで結果を返す（CI向け）")
+	fmt.Fprintln(w, "         0: 変換対象なし / 1: 書き換えが必要 / 2: 変換できない Form:: が残る / 3: エラー")
+	fmt.Fprintln(w, " --stdin 標準入力のテンプレートを変換して標準出力へ書き出す")
+	fmt.Fprintln(w, " --stdin-filename <名前> 標準入力モードの診断メッセージで使うファイル名")
+	fmt.Fprintln(w, " --config <ファイル> 設定ファイルを指定（既定: 対象パスから遡って .ffr.yaml / .ffr.yml / .ffr.json を探索）")
+	fmt.Fprintln(w, " --include <グロブ> 一致するファイルだけを処理する（複数指定可、** で任意の階層）")
+	fmt.Fprintln(w, " --exclude <グロブ> 一致するファイル・ディレクトリを除外する（複数指定可）")
+	fmt.Fprintln(w, " --no-ignore .gitignore / .ffrignore と既定の除外（storage/framework/views）を無効にする")
+	fmt.Fprintln(w, " --git-changed <ref> ref から変更されたファイルだけを処理する")
+	fmt.Fprintln(w, " --git-staged ステージ済みのファイルだけを処理する（pre-commit フック向け）")
+	fmt.Fprintln(w, " --force 作業ツリーに未コミットの変更があっても書き換える")
+	fmt.Fprintln(w, " --report json 実行結果を JSON で標準出力へ書き出す（メッセージは標準エラーへ）")
+	fmt.Fprintln(w, " --report-file <ファイル> JSON レポートをファイルへ書き出す")
+	fmt.Fprintln(w, " -j, --jobs <数> 並列に変換するファイル数（既定: CPU 数）")
+	fmt.Fprintln(w, " --transaction, --all-or-nothing 全ファイルの変換に成功した場合のみまとめて書き込む")
+	fmt.Fprintln(w, " --backup 書き換え前の内容を .ffr-backup/<タイムスタンプ>/ に保存する")
+	fmt.Fprintln(w, " --backup-dir <ディレクトリ> バックアップの保存先（--backup を含む）")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "サブコマンド:")
+	fmt.Fprintln(w, " restore [<バックアップID>|latest] バックアップから変換前の内容を復元する（--list で一覧表示）")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "例:")
+	fmt.Fprintln(w, " go run form_facade_replacer.go resources/views/hoge")
+	fmt.Fprintln(w, " go run form_facade_replacer.go resources/views/hoge/fuga.blade.php")
+	fmt.Fprintln(w, " go run form_facade_replacer.go --diff --color resources/views/hoge")
+	fmt.Fprintln(w, " go run form_facade_replacer.go --patch ffr.patch resources/views/hoge")
+	fmt.Fprintln(w, " go run form_facade_replacer.go --exclude 'vendor/**' --include 'admin/**/*.blade.php' resources/views")
+	fmt.Fprintln(w, " go run form_facade_replacer.go --check resources/views")
+	fmt.Fprintln(w, " go run form_facade_replacer.go --git-changed origin/main resources/views")
+	fmt.Fprintln(w, " cat edit.blade.php | go run form_facade_replacer.go - > converted.blade.php")
 }
 
 // printVersion はバージョンとビルド時刻を表示する。
-func printVersion() {
-	fmt.Printf("Form Facade Replacer %s\n", version)
-	fmt.Printf("Build Date: %s\n", buildDate)
-	fmt.Println()
-	fmt.Println("Laravel Form Facade を HTML に変換する高性能 Go ツール")
-	fmt.Println("https://github.com/ryohirano/form-facade-replacer")
+func printVersion(w io.Writer) {
+	fmt.Fprintf(w, "Form Facade Replacer %s\n", version)
+	fmt.Fprintf(w, "Build Date: %s\n", buildDate)
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Laravel Form Facade を HTML に変換する高性能 Go ツール")
+	fmt.Fprintln(w, "https://github.com/ryohirano/form-facade-replacer")
 }
 
 // --- Dynamic Attributes ---
@@ -49,153 +80,22 @@ type DynamicAttributePair struct {
 	Value string // 動的値（例: $condition ? 'disabled' : null）
 }
 
-// detectDynamicAttributes は文字列から動的属性を抽出して配列で返す。
+// detectDynamicAttributes はオプション配列から、キーが三項演算子の要素を動的属性として抽出する。
 func detectDynamicAttributes(attrs string) []DynamicAttributePair {
-	return extractDynamicAttributesBalanced(attrs)
-}
-
-// extractDynamicAttributesBalanced はカンマ分割時に括弧/クォートのバランスを考慮して抽出する。
-func extractDynamicAttributesBalanced(attrs string) []DynamicAttributePair {
+	options := parseOptionsArray(attrs)
+	if options == nil {
+		return nil
+	}
 	var pairs []DynamicAttributePair
-	var current strings.Builder
-	var parenCount, bracketCount, braceCount int
-	var inQuotes bool
-	var quoteChar rune
-	var escapeNext bool
-
-	i := 0
-	for i < len(attrs) {
-		char := rune(attrs[i])
-
-		if escapeNext {
-			current.WriteRune(char)
-			escapeNext = false
-			i++
+	for _, item := range options.Items {
+		if item.Key == nil || item.Key.Kind != phpTernary {
 			continue
 		}
-
-		if char == '\\' && inQuotes {
-			current.WriteRune(char)
-			escapeNext = true
-			i++
-			continue
-		}
-
-		if !inQuotes && (char == '"' || char == '\'') {
-			inQuotes = true
-			quoteChar = char
-		} else if inQuotes && char == quoteChar {
-			inQuotes = false
-			quoteChar = 0
-		}
-
-		if !inQuotes {
-			switch char {
-			case '(':
-				parenCount++
-			case ')':
-				parenCount--
-			case '[':
-				bracketCount++
-			case ']':
-				bracketCount--
-			case '{':
-				braceCount++
-			case '}':
-				braceCount--
-			case ',':
-				if parenCount == 0 && bracketCount == 0 && braceCount == 0 {
-					// 区切り文字に到達
-					attributeStr := strings.TrimSpace(current.String())
-					if isDynamicAttribute(attributeStr) {
-						pair := parseDynamicAttributePair(attributeStr)
-						if pair.Key != "" && pair.Value != "" {
-							pairs = append(pairs, pair)
-						}
-					}
-					current.Reset()
-					i++
-					continue
-				}
-			}
-		}
-
-		current.WriteRune(char)
-		i++
-	}
-
-	// 最後の属性を処理
-	if current.Len() > 0 {
-		attributeStr := strings.TrimSpace(current.String())
-		if isDynamicAttribute(attributeStr) {
-			pair := parseDynamicAttributePair(attributeStr)
-			if pair.Key != "" && pair.Value != "" {
-				pairs = append(pairs, pair)
-			}
-		}
+		pairs = append(pairs, DynamicAttributePair{Key: item.Key.Src, Value: item.Value.Src})
 	}
-
 	return pairs
 }
 
-// parseDynamicAttributePair は文字列から key=>value 形式の動的属性を解析する。
-func parseDynamicAttributePair(input string) DynamicAttributePair {
-	patterns := []string{
-		// 1. 標準的なパターン: $変数 ? 'キー' : 'キー' => 値
-		`(^\$\w+(?:\[[^\]]*\])*(?:->[a-zA-Z_]\w*\([^)]*\))?\s*\?\s*'[^']*'\s*:\s*'[^']*')\s*=>\s*(.+)`,
-		// 2. 複雑な条件: (条件) ? 'キー' : 'キー' => 値
-		`(^\(.*?\)\s*\?\s*'[^']*'\s*:\s*'[^']*')\s*=>\s*(.+)`,
-		// 3. ネストした三項演算子: $変数 ? (ネスト) : 'キー' => 値
-		`(^\$\w+(?:\[[^\]]*\])*\s*\?\s*\([^)]+\)\s*:\s*'[^']*')\s*=>\s*(.+)`,
-		// 4. 複雑な条件式（&&、||演算子含む）: $変数->method() && $変数->method() ? 'キー' : 'キー' => 値
-		`(^\$\w+(?:\[[^\]]*\])*(?:->[a-zA-Z_]\w*\([^)]*\))?(?:\s*&&\s*\$\w+(?:\[[^\]]*\])*(?:->[a-zA-Z_]\w*\([^)]*\))?)*\s*\?\s*'[^']*'\s*:\s*'[^']*')\s*=>\s*(.+)`,
-		// 5. 複雑な条件式（||演算子含む）: $変数->method() || $変数->method() ? 'キー' : 'キー' => 値
-		`(^\$\w+(?:\[[^\]]*\])*(?:->[a-zA-Z_]\w*\([^)]*\))?(?:\s*\|\|\s*\$\w+(?:\[[^\]]*\])*(?:->[a-zA-Z_]\w*\([^)]*\))?)*\s*\?\s*'[^']*'\s*:\s*'[^']*')\s*=>\s*(.+)`,
-	}
-
-	for _, pattern := range patterns {
-		re := regexCache.GetRegex(pattern)
-		matches := re.FindStringSubmatch(input)
-
-		if len(matches) >= 3 {
-			key := strings.TrimSpace(matches[1])
-			value := strings.TrimSpace(matches[2])
-			return DynamicAttributePair{
-				Key:   key,
-				Value: value,
-			}
-		}
-	}
-
-	return DynamicAttributePair{}
-}
-
-// isDynamicAttribute は与えられた文字列が動的属性の形かどうかを判定する。
-func isDynamicAttribute(input string) bool {
-	// より包括的な動的属性パターンを検出する正規表現
-	// 1. 単純な変数: $変数 ? 'キー' : 'キー' => 値
-	// 2. 複雑な条件: (条件) ? 'キー' : 'キー' => 値
-	// 3. ネストした三項演算子: $変数 ? (ネスト) : 'キー' => 値
-	// 4. 複雑な条件式（&&、||演算子含む）
-	patterns := []string{
-		`^\$\w+(?:\[[^\]]*\])*(?:->[a-zA-Z_]\w*\([^)]*\))?\s*\?\s*`, // $変数 ?
-		`^\(.*?\)\s*\?\s*`, // (条件) ?
-		`^\$\w+(?:\[[^\]]*\])*\s*\?\s*\([^)]+\)\s*:\s*'[^']*'\s*=>`, // $変数 ? (ネスト) : 'キー' =>
-		`^\$\w+(?:\[[^\]]*\])*\s*\?\s*'[^']*'\s*:\s*'[^']*'\s*=>`,   // $変数 ? 'キー' : 'キー' =>
-		`^\$\w+(?:\[[^\]]*\])*(?:->[a-zA-Z_]\w*\([^)]*\))?(?:\s*&&\s*\$\w+(?:\[[^\]]*\])*(?:->[a-zA-Z_]\w*\([^)]*\))?)*\s*\?\s*'[^']*'\s*:\s*'[^']*'\s*=>`,   // $変数 && $変数 ?
-		`^\$\w+(?:\[[^\]]*\])*(?:->[a-zA-Z_]\w*\([^)]*\))?(?:\s*\|\|\s*\$\w+(?:\[[^\]]*\])*(?:->[a-zA-Z_]\w*\([^)]*\))?)*\s*\?\s*'[^']*'\s*:\s*'[^']*'\s*=>`, // $変数 || $変数 ?
-	}
-
-	for _, pattern := range patterns {
-		re := regexCache.GetRegex(pattern)
-		if re.MatchString(input) {
-			return true
-		}
-	}
-
-	return false
-}
-
 // processDynamicAttributes は動的属性を HTML 属性文字列へ展開する。
 func processDynamicAttributes(attrs string) string {
 	var result strings.Builder
@@ -218,59 +118,25 @@ func processDynamicAttributes(attrs string) string {
 }
 
 // --- Dispatcher ---
-// replaceFormPatterns は1ファイルの内容を各 replaceXXX に順次通し、結果を書き戻す。
+// replaceFormPatterns は1ファイルの内容を変換し、結果を書き戻す。
 func replaceFormPatterns(filePath string) error {
-	content, err := os.ReadFile(filePath)
+	result, err := convertFile(filePath)
 	if err != nil {
 		return err
 	}
+	return writeResult(result)
+}
 
-	text := string(content)
-	text = replaceFormOpen(text)
-	text = replaceFormClose(text)
-	text = replaceFormHidden(text)
-	text = replaceFormButton(text)
-	text = replaceFormTextarea(text)
-	text = replaceFormLabel(text)
-	text = replaceFormText(text)
-	text = replaceFormInput(text)
-	text = replaceFormNumber(text)
-	text = replaceFormSelect(text)
-	text = replaceFormCheckbox(text)
-	text = replaceFormSubmit(text)
-	text = replaceFormFile(text)
-	text = replaceFormEmail(text)
-	text = replaceFormPassword(text)
-	text = replaceFormUrl(text)
-	text = replaceFormTel(text)
-	text = replaceFormSearch(text)
-	text = replaceFormDate(text)
-	text = replaceFormTime(text)
-	text = replaceFormDatetime(text)
-	text = replaceFormRange(text)
-	text = replaceFormColor(text)
-	text = replaceFormRadio(text)
-
-	return os.WriteFile(filePath, []byte(text), 0644)
+// convertFormPatterns はテキスト内の Form:: 呼び出しを1回の走査で HTML に変換した結果を返す。
+func convertFormPatterns(text string) string {
+	converted, _ := convertTemplate(text)
+	return converted
 }
 
 // --- Hidden ---
 // replaceFormHidden は Form::hidden(...) を <input type="hidden"> に置換する。
 func replaceFormHidden(text string) string {
-	patterns := []string{
-		`(?s)\{\!\!\s*Form::hidden\(\s*(.*?)\s*\)\s*\!\!\}`,
-		`(?s)\{\{\s*Form::hidden\(\s*(.*?)\s*\)\s*\}\}`,
-	}
-
-	for _, pattern := range patterns {
-		re := regexCache.GetRegex(pattern)
-		text = re.ReplaceAllStringFunc(text, func(match string) string {
-			content := re.FindStringSubmatch(match)[1]
-			params := extractParamsAdvanced(content)
-			return processFormHidden(params)
-		})
-	}
-	return text
+	return replaceFormMethod(text, "hidden")
 }
 
 // processFormHidden は hidden の name/value/属性を整形して最終HTMLを生成する。
@@ -288,11 +154,12 @@ func processFormHidden(params []string) string {
 
 	// 属性処理の統一
 	attrProcessor := &AttributeProcessor{
-		Order: []string{"id", "class"},
-		Patterns: map[string]string{
-			"id":    `'id'\s*=>\s*'([^']+)'`,
-			"class": `'class'\s*=>\s*'([^']+)'`,
+		Order: attributeOrder("hidden", []string{"id", "class"}),
+		Values: map[string]attrValue{
+			"id":    attrText,
+			"class": attrText,
 		},
+		Skip: []string{"type", "name", "value"},
 	}
 
 	extraAttrs := ""
@@ -313,7 +180,7 @@ func processFormInputDynamic(params []string) string {
 	}
 
 	// 最初のパラメータからinput typeを取得
-	inputType := strings.Trim(params[0], `'"`)
+	inputType := bladeContent(params[0])
 
 	// 残りのパラメータ（name, value, attributes）を processFormInput に渡す
 	remainingParams := params[1:]
@@ -324,25 +191,5 @@ func processFormInputDynamic(params []string) string {
 // --- Color ---
 // replaceFormColor は Form::color(...) を <input type="color"> に置換する。
 func replaceFormColor(text string) string {
-	// 複雑なネストに対応したパターンに変更
-	// (?s)フラグで改行を含む文字列のマッチを有効化
-	patterns := []string{
-		`(?s)\{\!\!\s*Form::color\(\s*(.*?)\s*\)\s*\!\!\}`,
-		`(?s)\{\{\s*Form::color\(\s*(.*?)\s*\)\s*\}\}`,
-	}
-
-	for _, pattern := range patterns {
-		re := regexCache.GetRegex(pattern)
-		text = re.ReplaceAllStringFunc(text, func(match string) string {
-			fullMatch := re.FindStringSubmatch(match)
-			if len(fullMatch) > 1 {
-				paramStr := fullMatch[1]
-				// バランスを考慮したパラメータ抽出に変更
-				params := extractParamsBalanced(paramStr)
-				return processFormInput("color", params)
-			}
-			return match
-		})
-	}
-	return text
+	return replaceFormMethod(text, "color")
 }
@@ -0,0 +1,286 @@
+// filter.go: ディレクトリ走査時の include/exclude グロブと .gitignore / .ffrignore による絞り込み。
+package ffr
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// 走査時に読み込む除外設定ファイル（.gitignore と同じ書式）
+var ignoreFileNames = []string{".gitignore", ".ffrignore"}
+
+// defaultExcludes は既定で除外するパス（Laravel のコンパイル済みビューキャッシュ）
+var defaultExcludes = []string{"**/storage/framework/views"}
+
+// ignoreRule は .gitignore 形式の1行分のルール。
+type ignoreRule struct {
+	pattern string // 除外設定ファイルのあるディレクトリからの相対グロブ
+	negate  bool   // "!" で始まる再包含ルール
+	dirOnly bool   // "/" で終わるディレクトリ専用ルール
+}
+
+// parseIgnoreRule は .gitignore の1行を解析する（空行・コメントは ok=false）。
+func parseIgnoreRule(line string) (ignoreRule, bool) {
+	line = strings.TrimRight(line, " \t\r")
+	if line == "" || strings.HasPrefix(line, "#") {
+		return ignoreRule{}, false
+	}
+	var rule ignoreRule
+	if strings.HasPrefix(line, "!") {
+		rule.negate = true
+		line = line[1:]
+	} else if strings.HasPrefix(line, `\`) {
+		// "\#" "\!" は先頭の文字そのものを表す
+		line = line[1:]
+	}
+	if strings.HasSuffix(line, "/") {
+		rule.dirOnly = true
+		line = strings.TrimRight(line, "/")
+	}
+	if line == "" {
+		return ignoreRule{}, false
+	}
+	rule.pattern = normalizeGlob(line)
+	return rule, true
+}
+
+// normalizeGlob はグロブを走査起点からの相対パターンにそろえる。
+// スラッシュを含まないパターンは、.gitignore と同様にどの階層の名前にも一致させる。
+func normalizeGlob(pattern string) string {
+	pattern = filepath.ToSlash(pattern)
+	pattern = strings.TrimPrefix(pattern, "./")
+	pattern = strings.TrimSuffix(pattern, "/")
+	if strings.HasPrefix(pattern, "/") {
+		return strings.TrimPrefix(pattern, "/")
+	}
+	if !strings.Contains(pattern, "/") {
+		return "**/" + pattern
+	}
+	return pattern
+}
+
+// validateGlob はグロブの構文を検証する。
+func validateGlob(pattern string) error {
+	for _, segment := range strings.Split(filepath.ToSlash(pattern), "/") {
+		if _, err := path.Match(segment, ""); err != nil {
+			return fmt.Errorf("不正なグロブパターンです: %s", pattern)
+		}
+	}
+	return nil
+}
+
+// matchGlob はスラッシュ区切りの相対パス name がグロブに一致するかを返す。
+// "**" は0個以上のディレクトリに一致する（末尾の "**" は配下の1つ以上の要素に一致する）。
+func matchGlob(pattern, name string) bool {
+	return matchGlobSegments(strings.Split(pattern, "/"), strings.Split(name, "/"))
+}
+
+func matchGlobSegments(pattern, name []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			rest := pattern[1:]
+			if len(rest) == 0 {
+				return len(name) > 0
+			}
+			for i := 0; i <= len(name); i++ {
+				if matchGlobSegments(rest, name[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(name) == 0 {
+			return false
+		}
+		if ok, _ := path.Match(pattern[0], name[0]); !ok {
+			return false
+		}
+		pattern, name = pattern[1:], name[1:]
+	}
+	return len(name) == 0
+}
+
+// fileFilter はディレクトリ走査で処理するファイルを絞り込む。
+type fileFilter struct {
+	root       string   // 走査の起点（絶対パス）
+	includes   []string // いずれかに一致するファイルだけを処理する（空なら全ファイル）
+	excludes   []string // 一致するファイル・ディレクトリを除外する
+	ignoreRoot string   // .gitignore を読み始めるディレクトリ（git リポジトリのルート）
+	useIgnore  bool
+	ignores    map[string][]ignoreRule // ディレクトリごとの除外ルール（読み込み済みキャッシュ）
+}
+
+// newFileFilter はコマンドライン・設定ファイルの指定から fileFilter を作成する。
+func newFileFilter(config *ReplacementConfig) (*fileFilter, error) {
+	root, err := filepath.Abs(config.TargetPath)
+	if err != nil {
+		return nil, err
+	}
+	f := &fileFilter{
+		root:       root,
+		ignoreRoot: root,
+		useIgnore:  !config.NoIgnore,
+		ignores:    make(map[string][]ignoreRule),
+	}
+	for _, pattern := range append(append([]string{}, settings.Include...), config.Include...) {
+		f.includes = append(f.includes, normalizeGlob(pattern))
+	}
+	excludes := append(append([]string{}, settings.Exclude...), config.Exclude...)
+	if f.useIgnore {
+		excludes = append(append([]string{}, defaultExcludes...), excludes...)
+		if gitRoot := findGitRoot(root); gitRoot != "" {
+			f.ignoreRoot = gitRoot
+		}
+	}
+	for _, pattern := range excludes {
+		f.excludes = append(f.excludes, normalizeGlob(pattern))
+	}
+	return f, nil
+}
+
+// findGitRoot は dir から親へ遡って .git のあるディレクトリを探す（見つからなければ空文字）。
+func findGitRoot(dir string) string {
+	for {
+		if _, err := os.Stat(filepath.Join(dir, ".git")); err == nil {
+			return dir
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+// skips は path を走査対象から外すかを返す（ディレクトリの場合は配下ごと外す）。
+func (f *fileFilter) skips(p string, isDir bool) (bool, error) {
+	abs, err := filepath.Abs(p)
+	if err != nil {
+		return false, err
+	}
+	rel, err := filepath.Rel(f.root, abs)
+	if err != nil || rel == "." {
+		return false, err
+	}
+	rel = filepath.ToSlash(rel)
+	if isDir && filepath.Base(abs) == ".git" {
+		return true, nil
+	}
+	for _, pattern := range f.excludes {
+		if matchGlob(pattern, rel) {
+			return true, nil
+		}
+	}
+	if f.useIgnore {
+		ignored, err := f.ignored(abs, isDir)
+		if err != nil || ignored {
+			return ignored, err
+		}
+	}
+	if isDir || len(f.includes) == 0 {
+		return false, nil
+	}
+	for _, pattern := range f.includes {
+		if matchGlob(pattern, rel) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// ignored は祖先ディレクトリの .gitignore / .ffrignore によって abs が除外されるかを返す。
+// 浅い階層のファイルから順に評価し、最後に一致したルールを採用する。
+func (f *fileFilter) ignored(abs string, isDir bool) (bool, error) {
+	var dirs []string
+	for dir := filepath.Dir(abs); ; dir = filepath.Dir(dir) {
+		dirs = append(dirs, dir)
+		if dir == f.ignoreRoot || dir == filepath.Dir(dir) {
+			break
+		}
+	}
+
+	ignored := false
+	for i := len(dirs) - 1; i >= 0; i-- {
+		rules, err := f.loadIgnoreRules(dirs[i])
+		if err != nil {
+			return false, err
+		}
+		if len(rules) == 0 {
+			continue
+		}
+		rel, err := filepath.Rel(dirs[i], abs)
+		if err != nil {
+			return false, err
+		}
+		rel = filepath.ToSlash(rel)
+		for _, rule := range rules {
+			if (!rule.dirOnly || isDir) && matchGlob(rule.pattern, rel) {
+				ignored = !rule.negate
+			}
+		}
+	}
+	return ignored, nil
+}
+
+// loadIgnoreRules は dir にある除外設定ファイルを読み込む（結果はキャッシュする）。
+func (f *fileFilter) loadIgnoreRules(dir string) ([]ignoreRule, error) {
+	if rules, ok := f.ignores[dir]; ok {
+		return rules, nil
+	}
+	var rules []ignoreRule
+	for _, name := range ignoreFileNames {
+		file, err := os.Open(filepath.Join(dir, name))
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s を読み込めません: %v", filepath.Join(dir, name), err)
+		}
+		scanner := bufio.NewScanner(file)
+		for scanner.Scan() {
+			if rule, ok := parseIgnoreRule(scanner.Text()); ok {
+				rules = append(rules, rule)
+			}
+		}
+		err = scanner.Err()
+		file.Close()
+		if err != nil {
+			return nil, fmt.Errorf("%s を読み込めません: %v", filepath.Join(dir, name), err)
+		}
+	}
+	f.ignores[dir] = rules
+	return rules, nil
+}
+
+// walkTargetFiles は対象ディレクトリ配下で、フィルタを通過した変換対象ファイルごとに fn を呼ぶ。
+func walkTargetFiles(config *ReplacementConfig, fn func(path string) error) error {
+	filter, err := newFileFilter(config)
+	if err != nil {
+		return err
+	}
+	return filepath.WalkDir(config.TargetPath, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		skip, err := filter.skips(path, d.IsDir())
+		if err != nil {
+			return err
+		}
+		if skip {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.IsDir() && hasTargetSuffix(path) && isGitSelected(config, path) {
+			return fn(path)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,251 @@
+package ffr
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestMatchGlob(t *testing.T) {
+	tests := []struct {
+		pattern  string
+		name     string
+		expected bool
+	}{
+		{"**/*.blade.php", "edit.blade.php", true},
+		{"**/*.blade.php", "users/admin/edit.blade.php", true},
+		{"users/*.blade.php", "users/edit.blade.php", true},
+		{"users/*.blade.php", "users/admin/edit.blade.php", false},
+		{"users/**/edit.blade.php", "users/edit.blade.php", true},
+		{"users/**/edit.blade.php", "users/a/b/edit.blade.php", true},
+		{"vendor/**", "vendor/mail/button.blade.php", true},
+		{"vendor/**", "vendor", false},
+		{"**/storage/framework/views", "storage/framework/views", true},
+		{"**/storage/framework/views", "app/storage/framework/views", true},
+		{"**/node_modules", "resources/node_modules", true},
+		{"admin/[a-c]*.blade.php", "admin/create.blade.php", true},
+		{"admin/[a-c]*.blade.php", "admin/edit.blade.php", false},
+		{"admin/?.blade.php", "admin/x.blade.php", true},
+	}
+
+	for _, tt := range tests {
+		if result := matchGlob(tt.pattern, tt.name); result != tt.expected {
+			t.Errorf("matchGlob(%q, %q) = %v, want %v", tt.pattern, tt.name, result, tt.expected)
+		}
+	}
+}
+
+func TestParseIgnoreRule(t *testing.T) {
+	tests := []struct {
+		line     string
+		expected ignoreRule
+		ok       bool
+	}{
+		{"", ignoreRule{}, false},
+		{"# comment", ignoreRule{}, false},
+		{"vendor/", ignoreRule{pattern: "**/vendor", dirOnly: true}, true},
+		{"/public", ignoreRule{pattern: "public"}, true},
+		{"*.blade.php  ", ignoreRule{pattern: "**/*.blade.php"}, true},
+		{"!keep.blade.php", ignoreRule{pattern: "**/keep.blade.php", negate: true}, true},
+		{"mail/**/*.blade.php", ignoreRule{pattern: "mail/**/*.blade.php"}, true},
+		{`\#hash.blade.php`, ignoreRule{pattern: "**/#hash.blade.php"}, true},
+	}
+
+	for _, tt := range tests {
+		result, ok := parseIgnoreRule(tt.line)
+		if ok != tt.ok || result != tt.expected {
+			t.Errorf("parseIgnoreRule(%q) = %+v, %v, want %+v, %v", tt.line, result, ok, tt.expected, tt.ok)
+		}
+	}
+}
+
+// setupFilterTest はフィルタ検証用のディレクトリ構成を作成する。
+func setupFilterTest(t *testing.T) string {
+	t.Helper()
+	root := t.TempDir()
+	files := map[string]string{
+		".gitignore":                                 "/vendor/\nbuild\n",
+		"resources/views/.ffrignore":                 "legacy/*\n!legacy/keep.blade.php\n",
+		"resources/views/users/edit.blade.php":       "{{ Form::text('name') }}",
+		"resources/views/admin/index.blade.php":      "{{ Form::text('q') }}",
+		"resources/views/legacy/old.blade.php":       "{{ Form::text('old') }}",
+		"resources/views/legacy/keep.blade.php":      "{{ Form::text('keep') }}",
+		"resources/views/build/cache.blade.php":      "{{ Form::text('cache') }}",
+		"vendor/package/views/form.blade.php":        "{{ Form::text('vendor') }}",
+		"storage/framework/views/compiled.blade.php": "{{ Form::text('compiled') }}",
+	}
+	for name, content := range files {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create dir: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to create test file %s: %v", name, err)
+		}
+	}
+	if err := os.Mkdir(filepath.Join(root, ".git"), 0755); err != nil {
+		t.Fatalf("Failed to create .git: %v", err)
+	}
+	return root
+}
+
+// collectTargetFiles は walkTargetFiles が返すファイルを root からの相対パスで返す。
+func collectTargetFiles(t *testing.T, config *ReplacementConfig, root string) []string {
+	t.Helper()
+	files := []string{}
+	err := walkTargetFiles(config, func(path string) error {
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		files = append(files, filepath.ToSlash(rel))
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("walkTargetFiles() error: %v", err)
+	}
+	sort.Strings(files)
+	return files
+}
+
+func TestWalkTargetFilesFilters(t *testing.T) {
+	useSettings(t, defaultSettings())
+	root := setupFilterTest(t)
+
+	tests := []struct {
+		name     string
+		target   string
+		include  []string
+		exclude  []string
+		noIgnore bool
+		expected []string
+	}{
+		{
+			name:   "Ignore files and default excludes",
+			target: root,
+			expected: []string{
+				"resources/views/admin/index.blade.php",
+				"resources/views/legacy/keep.blade.php",
+				"resources/views/users/edit.blade.php",
+			},
+		},
+		{
+			name:   "Parent .gitignore applies to subdirectory target",
+			target: filepath.Join(root, "resources", "views"),
+			expected: []string{
+				"resources/views/admin/index.blade.php",
+				"resources/views/legacy/keep.blade.php",
+				"resources/views/users/edit.blade.php",
+			},
+		},
+		{
+			name:     "Include glob",
+			target:   root,
+			include:  []string{"resources/**/users/*.blade.php"},
+			expected: []string{"resources/views/users/edit.blade.php"},
+		},
+		{
+			name:    "Exclude directory by name",
+			target:  root,
+			exclude: []string{"admin"},
+			expected: []string{
+				"resources/views/legacy/keep.blade.php",
+				"resources/views/users/edit.blade.php",
+			},
+		},
+		{
+			name:     "No ignore",
+			target:   root,
+			noIgnore: true,
+			expected: []string{
+				"resources/views/admin/index.blade.php",
+				"resources/views/build/cache.blade.php",
+				"resources/views/legacy/keep.blade.php",
+				"resources/views/legacy/old.blade.php",
+				"resources/views/users/edit.blade.php",
+				"storage/framework/views/compiled.blade.php",
+				"vendor/package/views/form.blade.php",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := newReplacementConfig()
+			config.TargetPath = tt.target
+			config.Include = tt.include
+			config.Exclude = tt.exclude
+			config.NoIgnore = tt.noIgnore
+			result := collectTargetFiles(t, config, root)
+			if !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("walkTargetFiles() = %v, want %v", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestFiltersFromSettings(t *testing.T) {
+	useSettings(t, &Settings{
+		Suffixes: []string{".blade.php"},
+		Include:  []string{"resources/**"},
+		Exclude:  []string{"users/"},
+	})
+	root := setupFilterTest(t)
+
+	config := newReplacementConfig()
+	config.TargetPath = root
+	expected := []string{
+		"resources/views/admin/index.blade.php",
+		"resources/views/legacy/keep.blade.php",
+	}
+	if result := collectTargetFiles(t, config, root); !reflect.DeepEqual(result, expected) {
+		t.Errorf("walkTargetFiles() = %v, want %v", result, expected)
+	}
+}
+
+func TestSummarySkipsFilteredFiles(t *testing.T) {
+	useSettings(t, defaultSettings())
+	root := setupFilterTest(t)
+
+	config := newReplacementConfig()
+	config.TargetPath = root
+	config.Exclude = []string{"resources/views/admin/**"}
+	if err := processBladeFiles(config); err != nil {
+		t.Fatalf("processBladeFiles() error: %v", err)
+	}
+	if config.FileCount != 2 {
+		t.Errorf("FileCount = %d, want 2", config.FileCount)
+	}
+
+	// 除外したファイルには Form:: が残っているが、サマリーの残存一覧には含めない
+	if remaining := findRemainingFormFacades(config); len(remaining) != 0 {
+		t.Errorf("findRemainingFormFacades() = %v, want none", remaining)
+	}
+	content, _ := os.ReadFile(filepath.Join(root, "vendor", "package", "views", "form.blade.php"))
+	if string(content) != "{{ Form::text('vendor') }}" {
+		t.Errorf("ignored file was modified:\n%s", string(content))
+	}
+}
+
+func TestParseArgsFilters(t *testing.T) {
+	config := newReplacementConfig()
+	args := []string{"--include", "admin/**", "--exclude=vendor/**", "--include=users/*.blade.php", "--no-ignore", "views"}
+	if _, err := parseArgs(args, config); err != nil {
+		t.Fatalf("parseArgs() error: %v", err)
+	}
+	if !reflect.DeepEqual(config.Include, []string{"admin/**", "users/*.blade.php"}) {
+		t.Errorf("Include = %v", config.Include)
+	}
+	if !reflect.DeepEqual(config.Exclude, []string{"vendor/**"}) {
+		t.Errorf("Exclude = %v", config.Exclude)
+	}
+	if !config.NoIgnore {
+		t.Error("NoIgnore should be true")
+	}
+
+	if _, err := parseArgs([]string{"--exclude", "[a-", "views"}, newReplacementConfig()); err == nil {
+		t.Error("parseArgs() should reject an invalid glob")
+	}
+}
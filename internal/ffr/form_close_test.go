@@ -185,9 +185,9 @@ func TestReplaceFormCloseEdgeCases(t *testing.T) {
 			expected: `前の内容 </form> 後の内容`,
 		},
 		{
-			name:     "Form::close() in Blade comments (should still replace)",
+			name:     "Form::close() in Blade comments (left untouched)",
 			input:    `{{-- Comment: {!! Form::close() !!} --}} {!! Form::close() !!}`,
-			expected: `{{-- Comment: </form> --}} </form>`,
+			expected: `{{-- Comment: {!! Form::close() !!} --}} </form>`,
 		},
 	}
 
@@ -42,7 +42,7 @@ func TestFormColor(t *testing.T) {
     'id' => 'custom-color-input',
     'title' => 'Choose custom color'
 ]) !!}`,
-			expected: `<input type="color" name="custom_color" value="{{ old('custom_color') }}" class="form-control custom-color-picker" id="custom-color-input">`,
+			expected: `<input type="color" name="custom_color" value="{{ old('custom_color') }}" class="form-control custom-color-picker" id="custom-color-input" title="Choose custom color">`,
 		},
 		{
 			name:     "Color with default value",
@@ -62,12 +62,12 @@ func TestFormColor(t *testing.T) {
 		{
 			name:     "Color with list attribute",
 			input:    `{{ Form::color('palette_color', old('palette_color'), ['list' => 'color_presets', 'class' => 'palette-picker']) }}`,
-			expected: `<input type="color" name="palette_color" value="{{ old('palette_color') }}" class="palette-picker">`,
+			expected: `<input type="color" name="palette_color" value="{{ old('palette_color') }}" class="palette-picker" list="color_presets">`,
 		},
 		{
 			name:     "Color with onchange event",
 			input:    `{{ Form::color('preview_color', '#000000', ['onchange' => 'updatePreview(this.value)', 'class' => 'preview-color']) }}`,
-			expected: `<input type="color" name="preview_color" value="{{ #000000 }}" class="preview-color">`,
+			expected: `<input type="color" name="preview_color" value="{{ #000000 }}" class="preview-color" onchange="updatePreview(this.value)">`,
 		},
 	}
 
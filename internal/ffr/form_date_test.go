@@ -28,7 +28,7 @@ func TestFormDate(t *testing.T) {
 		{
 			name:     "Date with attributes",
 			input:    `{{ Form::date('birth_date', old('birth_date'), ['class' => 'form-control', 'max' => '2023-12-31']) }}`,
-			expected: `<input type="date" name="birth_date" value="{{ old('birth_date') }}" class="form-control">`,
+			expected: `<input type="date" name="birth_date" value="{{ old('birth_date') }}" class="form-control" max="2023-12-31">`,
 		},
 		{
 			name:     "Date with double exclamation marks",
@@ -42,7 +42,7 @@ func TestFormDate(t *testing.T) {
     'id' => 'appointment-date-picker',
     'min' => '2023-01-01'
 ]) !!}`,
-			expected: `<input type="date" name="appointment_date" value="{{ old('appointment_date') }}" class="form-control appointment-date" id="appointment-date-picker">`,
+			expected: `<input type="date" name="appointment_date" value="{{ old('appointment_date') }}" class="form-control appointment-date" id="appointment-date-picker" min="2023-01-01">`,
 		},
 		{
 			name:     "Date with Carbon object",
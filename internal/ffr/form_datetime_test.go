@@ -28,7 +28,7 @@ func TestFormDatetime(t *testing.T) {
 		{
 			name:     "Datetime with attributes",
 			input:    `{{ Form::datetime('meeting_time', old('meeting_time'), ['class' => 'form-control', 'step' => '60']) }}`,
-			expected: `<input type="datetime-local" name="meeting_time" value="{{ old('meeting_time') }}" class="form-control">`,
+			expected: `<input type="datetime-local" name="meeting_time" value="{{ old('meeting_time') }}" class="form-control" step="60">`,
 		},
 		{
 			name:     "Datetime with double exclamation marks",
@@ -42,7 +42,7 @@ func TestFormDatetime(t *testing.T) {
     'id' => 'event-datetime-picker',
     'min' => '2023-01-01T00:00'
 ]) !!}`,
-			expected: `<input type="datetime-local" name="event_datetime" value="{{ old('event_datetime') }}" class="form-control event-datetime" id="event-datetime-picker">`,
+			expected: `<input type="datetime-local" name="event_datetime" value="{{ old('event_datetime') }}" class="form-control event-datetime" id="event-datetime-picker" min="2023-01-01T00:00">`,
 		},
 		{
 			name:     "Datetime with Carbon format",
@@ -62,7 +62,7 @@ func TestFormDatetime(t *testing.T) {
 		{
 			name:     "Datetime with min and max constraints",
 			input:    `{{ Form::datetime('booking_time', old('booking_time'), ['min' => '2023-01-01T09:00', 'max' => '2023-12-31T17:00', 'class' => 'booking-datetime']) }}`,
-			expected: `<input type="datetime-local" name="booking_time" value="{{ old('booking_time') }}" class="booking-datetime">`,
+			expected: `<input type="datetime-local" name="booking_time" value="{{ old('booking_time') }}" class="booking-datetime" min="2023-01-01T09:00" max="2023-12-31T17:00">`,
 		},
 	}
 
@@ -48,7 +48,7 @@ func TestFormInput(t *testing.T) {
 		{
 			name:     "Range input type",
 			input:    `{{ Form::input('range', 'volume', '50', ['min' => '0', 'max' => '100']) }}`,
-			expected: `<input type="range" name="volume" value="{{ 50 }}">`,
+			expected: `<input type="range" name="volume" value="{{ 50 }}" min="0" max="100">`,
 		},
 		{
 			name:     "Color input type",
@@ -142,6 +142,11 @@ func TestProcessFormInputDynamic(t *testing.T) {
 			params:   []string{"'url'", "'website'", "$company->website", "['placeholder' => 'https://example.com']"},
 			expected: `<input type="url" name="website" value="{{ $company->website }}" placeholder="https://example.com">`,
 		},
+		{
+			name:     "Type from a variable",
+			params:   []string{"$type", "'n'"},
+			expected: `<input type="{{ $type }}" name="n" value="">`,
+		},
 		{
 			name:     "Invalid params (less than 2)",
 			params:   []string{"'text'"},
@@ -35,6 +35,21 @@ func TestFormLabel(t *testing.T) {
 			input:    "{{ Form::label('password', null) }}",
 			expected: `<label for="password">{!! null !!}</label>`,
 		},
+		{
+			name:     "Label without text for an interpolated name",
+			input:    `{{ Form::label("items[$i]") }}`,
+			expected: `<label for="items[{{ $i }}]">{!! "items[$i]" !!}</label>`,
+		},
+		{
+			name:     "Label without text for a concatenated name",
+			input:    `{{ Form::label('item-' . $id) }}`,
+			expected: `<label for="item-{{ $id }}">{!! 'item-' . $id !!}</label>`,
+		},
+		{
+			name:     "Label without text for a name with a quote",
+			input:    `{{ Form::label("it's") }}`,
+			expected: `<label for="it's">{!! "it's" !!}</label>`,
+		},
 	}
 
 	for _, tt := range tests {
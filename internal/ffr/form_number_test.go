@@ -25,6 +25,16 @@ func TestFormNumber(t *testing.T) {
 			input:    "{{ Form::number('age', 25, ['class' => 'form-control', 'min' => 18, 'max' => 100]) }}",
 			expected: `<input type="number" name="age" value="{{ 25 }}" class="form-control" min="18" max="100">`,
 		},
+		{
+			name:     "Number field with string min, max and step",
+			input:    "{{ Form::number('n', null, ['step' => 'any', 'min' => '1', 'max' => '10']) }}",
+			expected: `<input type="number" name="n" min="1" max="10" step="any">`,
+		},
+		{
+			name:     "Number field with decimal min, max and step",
+			input:    "{{ Form::number('price', null, ['min' => 0.01, 'max' => 99.99, 'step' => 0.01]) }}",
+			expected: `<input type="number" name="price" min="0.01" max="99.99" step="0.01">`,
+		},
 		{
 			name:     "Number field with null value",
 			input:    "{{ Form::number('age', null) }}",
@@ -13,13 +13,13 @@ func TestReplaceFormOpen(t *testing.T) {
 	}{
 		{
 			name:     "Basic GET form with route",
-			input:    `{!! Form::open(['route' => 'user.index']) !!}`,
+			input:    `{!! Form::open(['route' => 'user.index', 'method' => 'GET']) !!}`,
 			expected: `<form action="{{ route('user.index') }}" method="GET">`,
 		},
 		{
 			name:     "GET from with none route",
 			input:    `{!! Form::open(['method' => 'get']) !!}`,
-			expected: `<form action="" method="get">`,
+			expected: `<form action="" method="GET">`,
 		},
 		{
 			name:  "POST form with route and CSRF",
@@ -36,19 +36,21 @@ func TestReplaceFormOpen(t *testing.T) {
 		{
 			name:  "Form with URL instead of route",
 			input: `{!! Form::open(['url' => '/users', 'method' => 'POST']) !!}`,
-			expected: `<form action="'/users'" method="POST">
+			expected: `<form action="{{ url('/users') }}" method="POST">
 {{ csrf_field() }}`,
 		},
 		{
-			name:     "Form with target attribute",
-			input:    `{!! Form::open(['route' => 'user.index', 'target' => '_blank']) !!}`,
-			expected: `<form action="{{ route('user.index') }}" method="GET" target="_blank">`,
+			name:  "Form with target attribute",
+			input: `{!! Form::open(['route' => 'user.index', 'target' => '_blank']) !!}`,
+			expected: `<form action="{{ route('user.index') }}" method="POST" target="_blank">
+{{ csrf_field() }}`,
 		},
 		{
 			name:  "Route with array parameters",
 			input: `{!! Form::open(['route' => ['user.update', ['id' => $user->id]], 'method' => 'PUT']) !!}`,
-			expected: `<form action="{{ route('user.update', ['id' => $user->id]) }}" method="PUT">
-{{ csrf_field() }}`,
+			expected: `<form action="{{ route('user.update', ['id' => $user->id]) }}" method="POST">
+{{ csrf_field() }}
+@method('PUT')`,
 		},
 		{
 			name:  "Blade syntax with double curly braces",
@@ -69,18 +71,21 @@ func TestReplaceFormOpen(t *testing.T) {
 		{
 			name:  "Multiple Form::open in same text",
 			input: `{!! Form::open(['route' => 'user.create']) !!} some content {!! Form::open(['route' => 'post.create', 'method' => 'POST']) !!}`,
-			expected: `<form action="{{ route('user.create') }}" method="GET"> some content <form action="{{ route('post.create') }}" method="POST">
+			expected: `<form action="{{ route('user.create') }}" method="POST">
+{{ csrf_field() }} some content <form action="{{ route('post.create') }}" method="POST">
 {{ csrf_field() }}`,
 		},
 		{
-			name:     "Empty form (no parameters)",
-			input:    `{!! Form::open([]) !!}`,
-			expected: `<form action="" method="GET">`,
+			name:  "Empty form (no parameters)",
+			input: `{!! Form::open([]) !!}`,
+			expected: `<form action="" method="POST">
+{{ csrf_field() }}`,
 		},
 		{
-			name:     "Double curly braces with extra spaces",
-			input:    `{{  Form::open(['route' => 'user.index'])  }}`,
-			expected: `<form action="{{ route('user.index') }}" method="GET">`,
+			name:  "Double curly braces with extra spaces",
+			input: `{{  Form::open(['route' => 'user.index'])  }}`,
+			expected: `<form action="{{ route('user.index') }}" method="POST">
+{{ csrf_field() }}`,
 		},
 		{
 			name:  "Double curly braces with complex spacing",
@@ -91,7 +96,7 @@ func TestReplaceFormOpen(t *testing.T) {
 		{
 			name:  "Double curly braces with URL",
 			input: `{{ Form::open(['url' => '/test', 'method' => 'POST']) }}`,
-			expected: `<form action="'/test'" method="POST">
+			expected: `<form action="{{ url('/test') }}" method="POST">
 {{ csrf_field() }}`,
 		},
 		{
@@ -100,25 +105,29 @@ func TestReplaceFormOpen(t *testing.T) {
     'route' => 'user.update',
     'method' => 'PUT'
 ]) }}`,
-			expected: `<form action="{{ route('user.update') }}" method="PUT">
-{{ csrf_field() }}`,
+			expected: `<form action="{{ route('user.update') }}" method="POST">
+{{ csrf_field() }}
+@method('PUT')`,
 		},
 		{
 			name:  "Mixed brackets in same text",
 			input: `{!! Form::open(['route' => 'user.create']) !!} and {{ Form::open(['route' => 'user.edit', 'method' => 'PUT']) }}`,
-			expected: `<form action="{{ route('user.create') }}" method="GET"> and <form action="{{ route('user.edit') }}" method="PUT">
-{{ csrf_field() }}`,
+			expected: `<form action="{{ route('user.create') }}" method="POST">
+{{ csrf_field() }} and <form action="{{ route('user.edit') }}" method="POST">
+{{ csrf_field() }}
+@method('PUT')`,
 		},
 		{
 			name:  "Double curly braces with array route parameters",
 			input: `{{ Form::open(['route' => ['user.update', ['id' => $user->id]], 'method' => 'PATCH']) }}`,
-			expected: `<form action="{{ route('user.update', ['id' => $user->id]) }}" method="PATCH">
-{{ csrf_field() }}`,
+			expected: `<form action="{{ route('user.update', ['id' => $user->id]) }}" method="POST">
+{{ csrf_field() }}
+@method('PATCH')`,
 		},
 		{
 			name:  "Nested array access in route parameters (user example)",
 			input: `{!! Form::open(['route' => ['admin.contents_manage.store.edit.confirm', ['id' => $contentsData['targetData']['store_id']]], 'method' => 'post']) !!}`,
-			expected: `<form action="{{ route('admin.contents_manage.store.edit.confirm', ['id' => $contentsData['targetData']['store_id']]) }}" method="post">
+			expected: `<form action="{{ route('admin.contents_manage.store.edit.confirm', ['id' => $contentsData['targetData']['store_id']]) }}" method="POST">
 {{ csrf_field() }}`,
 		},
 		{
@@ -146,9 +155,10 @@ func TestProcessFormOpen(t *testing.T) {
 		expected string
 	}{
 		{
-			name:     "Basic route",
-			input:    `'route' => 'user.index'`,
-			expected: `<form action="{{ route('user.index') }}" method="GET">`,
+			name:  "Basic route",
+			input: `'route' => 'user.index'`,
+			expected: `<form action="{{ route('user.index') }}" method="POST">
+{{ csrf_field() }}`,
 		},
 		{
 			name:  "Route with POST method",
@@ -158,13 +168,13 @@ func TestProcessFormOpen(t *testing.T) {
 		},
 		{
 			name:     "URL with GET method",
-			input:    `'url' => '/users'`,
-			expected: `<form action="'/users'" method="GET">`,
+			input:    `'url' => '/users', 'method' => 'GET'`,
+			expected: `<form action="{{ url('/users') }}" method="GET">`,
 		},
 		{
 			name:  "URL with POST method",
 			input: `'url' => '/users', 'method' => 'POST'`,
-			expected: `<form action="'/users'" method="POST">
+			expected: `<form action="{{ url('/users') }}" method="POST">
 {{ csrf_field() }}`,
 		},
 		{
@@ -180,9 +190,10 @@ func TestProcessFormOpen(t *testing.T) {
 {{ csrf_field() }}`,
 		},
 		{
-			name:     "Route with target attribute",
-			input:    `'route' => 'user.index', 'target' => '_blank'`,
-			expected: `<form action="{{ route('user.index') }}" method="GET" target="_blank">`,
+			name:  "Route with target attribute",
+			input: `'route' => 'user.index', 'target' => '_blank'`,
+			expected: `<form action="{{ route('user.index') }}" method="POST" target="_blank">
+{{ csrf_field() }}`,
 		},
 		{
 			name:  "Route with all attributes",
@@ -193,24 +204,27 @@ func TestProcessFormOpen(t *testing.T) {
 		{
 			name:  "Route with array parameters",
 			input: `'route' => ['user.update', ['id' => $user->id]], 'method' => 'PUT'`,
-			expected: `<form action="{{ route('user.update', ['id' => $user->id]) }}" method="PUT">
-{{ csrf_field() }}`,
+			expected: `<form action="{{ route('user.update', ['id' => $user->id]) }}" method="POST">
+{{ csrf_field() }}
+@method('PUT')`,
 		},
 		{
 			name:  "Route with nested array access parameters",
 			input: `'route' => ['admin.contents_manage.store.edit.confirm', ['id' => $contentsData['targetData']['store_id']]], 'method' => 'post'`,
-			expected: `<form action="{{ route('admin.contents_manage.store.edit.confirm', ['id' => $contentsData['targetData']['store_id']]) }}" method="post">
+			expected: `<form action="{{ route('admin.contents_manage.store.edit.confirm', ['id' => $contentsData['targetData']['store_id']]) }}" method="POST">
 {{ csrf_field() }}`,
 		},
 		{
-			name:     "URL with route function",
-			input:    `'url' => route('user.index')`,
-			expected: `<form action="{{ route('user.index') }}" method="GET">`,
+			name:  "URL with route function",
+			input: `'url' => route('user.index')`,
+			expected: `<form action="{{ route('user.index') }}" method="POST">
+{{ csrf_field() }}`,
 		},
 		{
-			name:     "Empty content",
-			input:    ``,
-			expected: `<form action="" method="GET">`,
+			name:  "Empty content",
+			input: ``,
+			expected: `<form action="" method="POST">
+{{ csrf_field() }}`,
 		},
 	}
 
@@ -255,3 +269,142 @@ func TestFormOpenIntegration(t *testing.T) {
 		t.Errorf("Integration test failed.\nGot:\n%s\nWant:\n%s", result, expected)
 	}
 }
+
+func TestConvertTemplateMethodSpoofing(t *testing.T) {
+	tests := []struct {
+		name        string
+		methodField string
+		input       string
+		expected    string
+	}{
+		{
+			name:     "Lower-case DELETE",
+			input:    `{!! Form::open(['url' => '/users/1', 'method' => 'delete']) !!}`,
+			expected: "<form action=\"{{ url('/users/1') }}\" method=\"POST\">\n{{ csrf_field() }}\n@method('DELETE')",
+		},
+		{
+			name:     "Ternary between PUT and POST",
+			input:    `{!! Form::open(['url' => $url, 'method' => $isEdit ? 'PUT' : 'POST']) !!}`,
+			expected: "<form action=\"{{ $url }}\" method=\"POST\">\n{{ csrf_field() }}\n@if($isEdit)\n@method('PUT')\n@endif",
+		},
+		{
+			name:     "Ternary between GET and PATCH",
+			input:    `{!! Form::open(['method' => $search ? 'get' : 'patch']) !!}`,
+			expected: "<form action=\"\" method=\"{{ $search ? 'GET' : 'POST' }}\">\n@unless($search)\n{{ csrf_field() }}\n@endunless\n@unless($search)\n@method('PATCH')\n@endunless",
+		},
+		{
+			name:     "Ternary between PUT and DELETE",
+			input:    `{!! Form::open(['method' => $remove ? 'DELETE' : 'PUT']) !!}`,
+			expected: "<form action=\"\" method=\"POST\">\n{{ csrf_field() }}\n@if($remove)\n@method('DELETE')\n@else\n@method('PUT')\n@endif",
+		},
+		{
+			name:     "Variable method",
+			input:    `{!! Form::open(['method' => $method]) !!}`,
+			expected: "<form action=\"\" method=\"{{ strtoupper($method) === 'GET' ? 'GET' : 'POST' }}\">\n@unless(strtoupper($method) === 'GET')\n{{ csrf_field() }}\n@endunless\n@unless(in_array(strtoupper($method), ['GET', 'POST']))\n@method(strtoupper($method))\n@endunless",
+		},
+		{
+			name:        "Hidden input",
+			methodField: "input",
+			input:       `{!! Form::open(['method' => 'PUT']) !!}`,
+			expected:    "<form action=\"\" method=\"POST\">\n{{ csrf_field() }}\n<input type=\"hidden\" name=\"_method\" value=\"PUT\">",
+		},
+		{
+			name:        "Hidden input for a variable method",
+			methodField: "input",
+			input:       `{!! Form::open(['method' => $method]) !!}`,
+			expected:    "<form action=\"\" method=\"{{ strtoupper($method) === 'GET' ? 'GET' : 'POST' }}\">\n@unless(strtoupper($method) === 'GET')\n{{ csrf_field() }}\n@endunless\n@unless(in_array(strtoupper($method), ['GET', 'POST']))\n<input type=\"hidden\" name=\"_method\" value=\"{{ strtoupper($method) }}\">\n@endunless",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := defaultSettings()
+			if tt.methodField != "" {
+				s.MethodField = tt.methodField
+			}
+			useSettings(t, s)
+			if got, _ := convertTemplate(tt.input); got != tt.expected {
+				t.Errorf("convertTemplate() =\n%s\nwant:\n%s", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestConvertTemplateFormOpenOptions(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:     "files becomes enctype",
+			input:    `{!! Form::open(['url' => '/upload', 'method' => 'POST', 'files' => true, 'enctype' => 'text/plain', 'class' => 'f']) !!}`,
+			expected: "<form action=\"{{ url('/upload') }}\" method=\"POST\" enctype=\"multipart/form-data\" class=\"f\">\n{{ csrf_field() }}",
+		},
+		{
+			name:     "files without a method posts with CSRF",
+			input:    `{!! Form::open(['route' => 'photos.store', 'files' => true]) !!}`,
+			expected: "<form action=\"{{ route('photos.store') }}\" method=\"POST\" enctype=\"multipart/form-data\">\n{{ csrf_field() }}",
+		},
+		{
+			name:  "files from an expression",
+			input: `{!! Form::open(['url' => '/upload', 'files' => $hasUpload]) !!}`,
+			expected: `<form action="{{ url('/upload') }}" method="POST" @if($hasUpload) enctype="multipart/form-data" @endif>
+{{ csrf_field() }}`,
+		},
+		{
+			name:  "files false",
+			input: `{!! Form::open(['files' => false]) !!}`,
+			expected: `<form action="" method="POST">
+{{ csrf_field() }}`,
+		},
+		{
+			name:     "Controller action string",
+			input:    `{!! Form::open(['action' => 'UserController@store', 'method' => 'POST']) !!}`,
+			expected: "<form action=\"{{ action('UserController@store') }}\" method=\"POST\">\n{{ csrf_field() }}",
+		},
+		{
+			name:     "Controller action with parameters",
+			input:    `{!! Form::open(['action' => ['UserController@update', $user->id], 'method' => 'PUT']) !!}`,
+			expected: "<form action=\"{{ action('UserController@update', [$user->id]) }}\" method=\"POST\">\n{{ csrf_field() }}\n@method('PUT')",
+		},
+		{
+			name:  "Controller callable array",
+			input: `{!! Form::open(['action' => [UserController::class, 'store']]) !!}`,
+			expected: `<form action="{{ action([UserController::class, 'store']) }}" method="POST">
+{{ csrf_field() }}`,
+		},
+		{
+			name:  "Other options pass through",
+			input: `{!! Form::open(['url' => '/s', 'accept-charset' => 'UTF-8', 'novalidate', 'autocomplete' => 'off', 'data-remote' => 'true', 'onsubmit' => 'return confirm()']) !!}`,
+			expected: `<form action="{{ url('/s') }}" method="POST" accept-charset="UTF-8" novalidate autocomplete="off" data-remote="true" onsubmit="return confirm()">
+{{ csrf_field() }}`,
+		},
+		{
+			name:  "No arguments",
+			input: `{!! Form::open() !!}`,
+			expected: `<form action="" method="POST">
+{{ csrf_field() }}`,
+		},
+		{
+			name:  "Options in a variable",
+			input: `{!! Form::open($options) !!}`,
+			expected: "<form action=\"{{ isset($options['url']) ? url($options['url']) : (isset($options['route']) ? route(...(array) $options['route']) : (isset($options['action']) ? action(...(array) $options['action']) : '')) }}\" " +
+				"method=\"{{ strtoupper($options['method'] ?? 'POST') === 'GET' ? 'GET' : 'POST' }}\" @if(!empty($options['files'])) enctype=\"multipart/form-data\" @endif" +
+				"{{ new \\Illuminate\\View\\ComponentAttributeBag(\\Illuminate\\Support\\Arr::except($options, ['url', 'route', 'action', 'method', 'files'])) }}>\n" +
+				"@unless(strtoupper($options['method'] ?? 'POST') === 'GET')\n{{ csrf_field() }}\n@endunless\n" +
+				"@unless(in_array(strtoupper($options['method'] ?? 'POST'), ['GET', 'POST']))\n@method(strtoupper($options['method'] ?? 'POST'))\n@endunless",
+		},
+		{
+			name:     "Literal that is not an options array",
+			input:    `{!! Form::open('x') !!}`,
+			expected: `{!! Form::open('x') !!}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got, _ := convertTemplate(tt.input); got != tt.expected {
+				t.Errorf("convertTemplate() =\n%s\nwant:\n%s", got, tt.expected)
+			}
+		})
+	}
+}
@@ -33,7 +33,7 @@ func TestFormPassword(t *testing.T) {
     'id' => 'new-password',
     'minlength' => '8'
 ]) }}`,
-			expected: `<input type="password" name="new_password" value="" placeholder="New password" class="form-control password-field" id="new-password">`,
+			expected: `<input type="password" name="new_password" value="" placeholder="New password" class="form-control password-field" id="new-password" minlength="8">`,
 		},
 		{
 			name:     "Password with complex name",
@@ -40,6 +40,16 @@ func TestFormRadio(t *testing.T) {
 			input:    `{{ Form::radio('enabled', '1', old('enabled')) }}`,
 			expected: `<input type="radio" name="enabled" value="{{ '1' }}" @if(old('enabled')) checked @endif>`,
 		},
+		{
+			name:     "Radio with literal true checked",
+			input:    `{{ Form::radio('color', 'red', true) }}`,
+			expected: `<input type="radio" name="color" value="{{ 'red' }}" checked>`,
+		},
+		{
+			name:     "Radio with uppercase literal false",
+			input:    `{{ Form::radio('color', 'red', FALSE) }}`,
+			expected: `<input type="radio" name="color" value="{{ 'red' }}">`,
+		},
 		{
 			name:     "Radio with null checked (not checked)",
 			input:    `{{ Form::radio('disabled_option', 'no', null) }}`,
@@ -78,7 +88,7 @@ func TestFormRadio(t *testing.T) {
 		{
 			name:     "Radio with all attributes",
 			input:    `{{ Form::radio('field', 'value', true, ['id' => 'field-id', 'class' => 'radio-input', 'style' => 'margin: 5px;', 'onchange' => 'handleChange();', 'disabled' => '']) }}`,
-			expected: `<input type="radio" name="field" value="{{ 'value' }}" @if(true) checked @endif id="field-id" class="radio-input" style="margin: 5px;" onchange="handleChange();" disabled>`,
+			expected: `<input type="radio" name="field" value="{{ 'value' }}" checked id="field-id" class="radio-input" style="margin: 5px;" onchange="handleChange();" disabled>`,
 		},
 		{
 			name:     "Radio with double exclamation marks",
@@ -93,7 +103,7 @@ func TestFormRadio(t *testing.T) {
 		{
 			name:     "Radio with complex PHP string concatenation",
 			input:    `{{ Form::radio('data[' . $row['id'] . '][type]', $types[$index], $selected[$row['id']] ?? false, ['id' => 'type-' . $row['id']]) }}`,
-			expected: `<input type="radio" name="data[{{ $row['id'] }}][type]" value="{{ $types[$index] }}" @if($selected[$row['id']] ?? false) checked @endif id="type-">`,
+			expected: `<input type="radio" name="data[{{ $row['id'] }}][type]" value="{{ $types[$index] }}" @if($selected[$row['id']] ?? false) checked @endif id="{{ 'type-' . $row['id'] }}">`,
 		},
 		{
 			name: "Multi-line radio button",
@@ -112,7 +122,7 @@ func TestFormRadio(t *testing.T) {
     'style' => 'transform: scale(1.5); margin: 10px;',
     'onchange' => 'applyTheme("dark");'
 ]) }}`,
-			expected: `<input type="radio" name="theme" value="{{ 'dark' }}" @if($user->preferences['theme'] == 'dark') checked @endif id="theme-dark" class="theme-selector custom-radio" style="transform: scale(1.5); margin: 10px;" onchange="applyTheme("dark");">`,
+			expected: `<input type="radio" name="theme" value="{{ 'dark' }}" @if($user->preferences['theme'] == 'dark') checked @endif id="theme-dark" class="theme-selector custom-radio" style="transform: scale(1.5); margin: 10px;" onchange="applyTheme('dark');">`,
 		},
 		{
 			name:     "Radio with numeric value",
@@ -180,7 +190,7 @@ func TestProcessFormRadio(t *testing.T) {
 		{
 			name:     "Radio with all supported attributes",
 			params:   []string{"'field'", "'value'", "true", "['id' => 'field-id', 'class' => 'field-class', 'style' => 'color: blue;', 'onchange' => 'doSomething();', 'disabled' => 'disabled']"},
-			expected: `<input type="radio" name="field" value="{{ 'value' }}" @if(true) checked @endif id="field-id" class="field-class" style="color: blue;" onchange="doSomething();" disabled>`,
+			expected: `<input type="radio" name="field" value="{{ 'value' }}" checked id="field-id" class="field-class" style="color: blue;" onchange="doSomething();" disabled>`,
 		},
 		{
 			name:     "Radio with insufficient parameters",
@@ -28,12 +28,12 @@ func TestFormRange(t *testing.T) {
 		{
 			name:     "Range with attributes",
 			input:    `{{ Form::range('volume', old('volume', '50'), ['min' => '0', 'max' => '100', 'class' => 'form-range']) }}`,
-			expected: `<input type="range" name="volume" value="{{ old('volume', '50') }}" class="form-range">`,
+			expected: `<input type="range" name="volume" value="{{ old('volume', '50') }}" class="form-range" min="0" max="100">`,
 		},
 		{
 			name:     "Range with double exclamation marks",
 			input:    `{!! Form::range('temperature', $settings->temperature, ['min' => '16', 'max' => '30', 'step' => '0.5', 'class' => 'temp-slider']) !!}`,
-			expected: `<input type="range" name="temperature" value="{{ $settings->temperature }}" class="temp-slider">`,
+			expected: `<input type="range" name="temperature" value="{{ $settings->temperature }}" class="temp-slider" min="16" max="30" step="0.5">`,
 		},
 		{
 			name: "Multi-line range field",
@@ -44,27 +44,27 @@ func TestFormRange(t *testing.T) {
     'class' => 'form-control opacity-slider',
     'id' => 'opacity-range'
 ]) !!}`,
-			expected: `<input type="range" name="opacity" value="{{ old('opacity', '100') }}" class="form-control opacity-slider" id="opacity-range">`,
+			expected: `<input type="range" name="opacity" value="{{ old('opacity', '100') }}" class="form-control opacity-slider" id="opacity-range" min="0" max="100" step="1">`,
 		},
 		{
 			name:     "Range with percentage value",
 			input:    `{{ Form::range('progress', $task->completion_percentage, ['min' => '0', 'max' => '100', 'class' => 'progress-bar']) }}`,
-			expected: `<input type="range" name="progress" value="{{ $task->completion_percentage }}" class="progress-bar">`,
+			expected: `<input type="range" name="progress" value="{{ $task->completion_percentage }}" class="progress-bar" min="0" max="100">`,
 		},
 		{
 			name:     "Range with complex name",
 			input:    `{{ Form::range('settings[' . $category . '][value]', old('settings[' . $category . '][value]'), ['min' => '1', 'max' => '10', 'class' => 'setting-range']) }}`,
-			expected: `<input type="range" name="settings[{{ $category }}][value]" value="{{ old('settings[' . $category . '][value]') }}" class="setting-range">`,
+			expected: `<input type="range" name="settings[{{ $category }}][value]" value="{{ old('settings[' . $category . '][value]') }}" class="setting-range" min="1" max="10">`,
 		},
 		{
 			name:     "Range with decimal step",
 			input:    `{{ Form::range('rating', old('rating'), ['min' => '0', 'max' => '5', 'step' => '0.1', 'class' => 'rating-slider']) }}`,
-			expected: `<input type="range" name="rating" value="{{ old('rating') }}" class="rating-slider">`,
+			expected: `<input type="range" name="rating" value="{{ old('rating') }}" class="rating-slider" min="0" max="5" step="0.1">`,
 		},
 		{
 			name:     "Range with data attributes",
 			input:    `{{ Form::range('zoom', '1', ['min' => '0.5', 'max' => '3', 'step' => '0.1', 'data-default' => '1', 'class' => 'zoom-control']) }}`,
-			expected: `<input type="range" name="zoom" value="{{ 1 }}" class="zoom-control">`,
+			expected: `<input type="range" name="zoom" value="{{ 1 }}" class="zoom-control" min="0.5" max="3" step="0.1" data-default="1">`,
 		},
 	}
 
@@ -33,7 +33,7 @@ func TestFormSearch(t *testing.T) {
 		{
 			name:     "Search with double exclamation marks",
 			input:    `{!! Form::search('product_search', request('search'), ['class' => 'search-input', 'autocomplete' => 'off']) !!}`,
-			expected: `<input type="search" name="product_search" value="{{ request('search') }}" class="search-input">`,
+			expected: `<input type="search" name="product_search" value="{{ request('search') }}" class="search-input" autocomplete="off">`,
 		},
 		{
 			name: "Multi-line search field",
@@ -47,7 +47,7 @@ func TestFormSearch(t *testing.T) {
 		{
 			name:     "Search with results attribute",
 			input:    `{{ Form::search('site_search', old('site_search'), ['results' => '10', 'class' => 'site-search']) }}`,
-			expected: `<input type="search" name="site_search" value="{{ old('site_search') }}" class="site-search">`,
+			expected: `<input type="search" name="site_search" value="{{ old('site_search') }}" class="site-search" results="10">`,
 		},
 		{
 			name:     "Search with complex name",
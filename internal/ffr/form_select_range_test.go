@@ -0,0 +1,203 @@
+package ffr
+
+import (
+	"testing"
+)
+
+func TestFormSelectRange(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:  "Constant bounds",
+			input: "{{ Form::selectRange('number', 1, 3) }}",
+			expected: `<select name="number">
+<option value="1">1</option>
+<option value="2">2</option>
+<option value="3">3</option>
+</select>`,
+		},
+		{
+			name:  "Descending bounds with a literal selected value",
+			input: "{!! Form::selectRange('level', 2, -1, '0', ['class' => 'form-control']) !!}",
+			expected: `<select name="level" class="form-control">
+<option value="2">2</option>
+<option value="1">1</option>
+<option value="0" selected>0</option>
+<option value="-1">-1</option>
+</select>`,
+		},
+		{
+			name:  "Constant bounds with a selected expression",
+			input: "{{ Form::selectRange('day', 1, 2, $day) }}",
+			expected: `<select name="day">
+<option value="1" @if(1 == $day) selected @endif>1</option>
+<option value="2" @if(2 == $day) selected @endif>2</option>
+</select>`,
+		},
+		{
+			name:  "Expression bounds",
+			input: "{{ Form::selectRange('qty', 1, $max, $qty ?? 1) }}",
+			expected: `<select name="qty">
+@foreach(range(1, $max) as $__v)
+<option value="{{ $__v }}" @if($__v == ($qty ?? 1)) selected @endif>{{ $__v }}</option>
+@endforeach
+</select>`,
+		},
+		{
+			name:  "Non-literal bounds",
+			input: "{{ Form::selectRange('n', $min, $min + $count - 1) }}",
+			expected: `<select name="n">
+@foreach(range($min, $min + $count - 1) as $__v)
+<option value="{{ $__v }}">{{ $__v }}</option>
+@endforeach
+</select>`,
+		},
+		{
+			name:  "String bounds",
+			input: "{{ Form::selectRange('r', 'a', 'c') }}",
+			expected: `<select name="r">
+@foreach(range('a', 'c') as $__v)
+<option value="{{ $__v }}">{{ $__v }}</option>
+@endforeach
+</select>`,
+		},
+		{
+			name:  "Placeholder",
+			input: "{{ Form::selectRange('number', 1, 2, null, ['placeholder' => 'Number']) }}",
+			expected: `<select name="number">
+<option value="">Number</option>
+<option value="1">1</option>
+<option value="2">2</option>
+</select>`,
+		},
+		{
+			name:     "Missing end is left as is",
+			input:    "{{ Form::selectRange('number', 1) }}",
+			expected: "{{ Form::selectRange('number', 1) }}",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := replaceFormSelectRange(tt.input)
+			if result != tt.expected {
+				t.Errorf("Expected:\n%s\nGot:\n%s", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestFormSelectYear(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:  "Years from an expression",
+			input: "{{ Form::selectYear('year', date('Y'), date('Y') - 100, 1990, ['id' => 'year']) }}",
+			expected: `<select name="year" id="year">
+@foreach(range(date('Y'), date('Y') - 100) as $__v)
+<option value="{{ $__v }}" @if($__v == 1990) selected @endif>{{ $__v }}</option>
+@endforeach
+</select>`,
+		},
+		{
+			name:  "Constant years",
+			input: "{{ Form::selectYear('year', 2024, 2022, 2023) }}",
+			expected: `<select name="year">
+<option value="2024">2024</option>
+<option value="2023" selected>2023</option>
+<option value="2022">2022</option>
+</select>`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := replaceFormSelectYear(tt.input)
+			if result != tt.expected {
+				t.Errorf("Expected:\n%s\nGot:\n%s", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestFormSelectMonth(t *testing.T) {
+	tests := []struct {
+		name     string
+		locale   string
+		input    string
+		expected string
+	}{
+		{
+			name:  "Default format",
+			input: "{{ Form::selectMonth('month') }}",
+			expected: `<select name="month">
+@foreach(range(1, 12) as $__v)
+<option value="{{ $__v }}">{{ \Illuminate\Support\Carbon::create(null, $__v, 1)->translatedFormat('F') }}</option>
+@endforeach
+</select>`,
+		},
+		{
+			name:   "Selected value, attributes and locale",
+			locale: "ja",
+			input:  "{{ Form::selectMonth('month', $month, ['class' => 'form-select']) }}",
+			expected: `<select name="month" class="form-select">
+@foreach(range(1, 12) as $__v)
+<option value="{{ $__v }}" @if($__v == $month) selected @endif>{{ \Illuminate\Support\Carbon::create(null, $__v, 1)->locale('ja')->translatedFormat('F') }}</option>
+@endforeach
+</select>`,
+		},
+		{
+			name:  "strftime format",
+			input: "{{ Form::selectMonth('month', null, [], '%m - %b') }}",
+			expected: `<select name="month">
+@foreach(range(1, 12) as $__v)
+<option value="{{ $__v }}">{{ \Illuminate\Support\Carbon::create(null, $__v, 1)->translatedFormat('m - M') }}</option>
+@endforeach
+</select>`,
+		},
+		{
+			name:     "Unsupported strftime format is left as is",
+			input:    "{{ Form::selectMonth('month', null, [], '%A') }}",
+			expected: "{{ Form::selectMonth('month', null, [], '%A') }}",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := defaultSettings()
+			s.MonthLocale = tt.locale
+			useSettings(t, s)
+			result := replaceFormSelectMonth(tt.input)
+			if result != tt.expected {
+				t.Errorf("Expected:\n%s\nGot:\n%s", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestStrftimeToDateFormat(t *testing.T) {
+	tests := []struct {
+		format   string
+		expected string
+		ok       bool
+	}{
+		{"%B", "F", true},
+		{"%h %Y", "M Y", true},
+		{"%m月", "m月", true},
+		{"Month: %B", `\M\o\n\t\h: F`, true},
+		{"%d", "", false},
+		{"%", "", false},
+	}
+	for _, tt := range tests {
+		got, ok := strftimeToDateFormat(tt.format)
+		if got != tt.expected || ok != tt.ok {
+			t.Errorf("strftimeToDateFormat(%q) = %q, %v, want %q, %v", tt.format, got, ok, tt.expected, tt.ok)
+		}
+	}
+}
@@ -15,7 +15,7 @@ func TestFormSelect(t *testing.T) {
 			input: "{{ Form::select('country', ['jp' => 'Japan', 'us' => 'United States', 'uk' => 'United Kingdom']) }}",
 			expected: `<select name="country">
 @foreach(['jp' => 'Japan', 'us' => 'United States', 'uk' => 'United Kingdom'] as $key => $value)
-<option value="{{ $key }}" @if($key == ) selected @endif>{{ $value }}</option>
+<option value="{{ $key }}">{{ $value }}</option>
 @endforeach
 </select>`,
 		},
@@ -31,9 +31,38 @@ func TestFormSelect(t *testing.T) {
 		{
 			name:  "Select with attributes",
 			input: "{{ Form::select('country', ['jp' => 'Japan', 'us' => 'United States'], 'jp', ['class' => 'form-control', 'multiple' => 'multiple']) }}",
-			expected: `<select name="country" class="form-control">
+			expected: `<select name="country" class="form-control" multiple>
 @foreach(['jp' => 'Japan', 'us' => 'United States'] as $key => $value)
-<option value="{{ $key }}" @if($key == 'jp') selected @endif>{{ $value }}</option>
+<option value="{{ $key }}" @if(in_array($key, (array)'jp')) selected @endif>{{ $value }}</option>
+@endforeach
+</select>`,
+		},
+		{
+			name:  "Select with a null selected value",
+			input: "{{ Form::select('size', $sizes, null) }}",
+			expected: `<select name="size">
+@foreach($sizes as $key => $value)
+<option value="{{ $key }}">{{ $value }}</option>
+@endforeach
+</select>`,
+		},
+		{
+			name:  "Placeholder becomes a leading empty option",
+			input: "{{ Form::select('size', $sizes, null, ['placeholder' => 'Pick', 'class' => 'form-select']) }}",
+			expected: `<select name="size" class="form-select">
+<option value="">Pick</option>
+@foreach($sizes as $key => $value)
+<option value="{{ $key }}">{{ $value }}</option>
+@endforeach
+</select>`,
+		},
+		{
+			name:  "Placeholder from an expression",
+			input: "{{ Form::select('size', $sizes, null, ['placeholder' => __('Pick')]) }}",
+			expected: `<select name="size">
+<option value="">{{ __('Pick') }}</option>
+@foreach($sizes as $key => $value)
+<option value="{{ $key }}">{{ $value }}</option>
 @endforeach
 </select>`,
 		},
@@ -42,7 +71,7 @@ func TestFormSelect(t *testing.T) {
 			input: "{{ Form::select('empty', []) }}",
 			expected: `<select name="empty">
 @foreach([] as $key => $value)
-<option value="{{ $key }}" @if($key == ) selected @endif>{{ $value }}</option>
+<option value="{{ $key }}">{{ $value }}</option>
 @endforeach
 </select>`,
 		},
@@ -35,6 +35,16 @@ func TestFormSubmit(t *testing.T) {
 			input:    "{{ Form::submit(null) }}",
 			expected: `<button type="submit"></button>`,
 		},
+		{
+			name:     "Submit with a translated label",
+			input:    "{{ Form::submit(__('Save')) }}",
+			expected: `<button type="submit">{{ __('Save') }}</button>`,
+		},
+		{
+			name:     "Submit with an escaped quote",
+			input:    `{{ Form::submit('It\'s') }}`,
+			expected: `<button type="submit">It's</button>`,
+		},
 	}
 
 	for _, tt := range tests {
@@ -52,7 +52,7 @@ func TestFormTel(t *testing.T) {
 		{
 			name:     "Tel with pattern attribute",
 			input:    `{{ Form::tel('phone_number', old('phone_number'), ['pattern' => '[0-9]{3}-[0-9]{3}-[0-9]{4}', 'class' => 'formatted-phone']) }}`,
-			expected: `<input type="tel" name="phone_number" value="{{ old('phone_number') }}" class="formatted-phone">`,
+			expected: `<input type="tel" name="phone_number" value="{{ old('phone_number') }}" class="formatted-phone" pattern="[0-9]{3}-[0-9]{3}-[0-9]{4}">`,
 		},
 	}
 
@@ -25,6 +25,11 @@ func TestFormTextarea(t *testing.T) {
 			input:    "{{ Form::textarea('message', 'Default content', ['class' => 'form-control', 'rows' => 5, 'cols' => 30]) }}",
 			expected: `<textarea name="message" cols="30" rows="5" class="form-control">{{ 'Default content' }}</textarea>`,
 		},
+		{
+			name:     "Textarea with string cols and rows",
+			input:    "{{ Form::textarea('message', null, ['cols' => '2', 'rows' => '3']) }}",
+			expected: `<textarea name="message" cols="2" rows="3"></textarea>`,
+		},
 		{
 			name:     "Textarea with null value and attributes",
 			input:    "{{ Form::textarea('message', null, ['class' => 'form-control']) }}",
@@ -28,7 +28,7 @@ func TestFormTime(t *testing.T) {
 		{
 			name:     "Time with attributes",
 			input:    `{{ Form::time('meeting_time', old('meeting_time'), ['class' => 'form-control', 'step' => '300']) }}`,
-			expected: `<input type="time" name="meeting_time" value="{{ old('meeting_time') }}" class="form-control">`,
+			expected: `<input type="time" name="meeting_time" value="{{ old('meeting_time') }}" class="form-control" step="300">`,
 		},
 		{
 			name:     "Time with double exclamation marks",
@@ -42,7 +42,7 @@ func TestFormTime(t *testing.T) {
     'id' => 'working-hours-start',
     'step' => '900'
 ]) !!}`,
-			expected: `<input type="time" name="working_hours_start" value="{{ old('working_hours_start') }}" class="form-control working-time" id="working-hours-start">`,
+			expected: `<input type="time" name="working_hours_start" value="{{ old('working_hours_start') }}" class="form-control working-time" id="working-hours-start" step="900">`,
 		},
 		{
 			name:     "Time with Carbon format",
@@ -57,7 +57,7 @@ func TestFormTime(t *testing.T) {
 		{
 			name:     "Time with min and max",
 			input:    `{{ Form::time('office_hours', old('office_hours'), ['min' => '08:00', 'max' => '18:00', 'class' => 'office-time']) }}`,
-			expected: `<input type="time" name="office_hours" value="{{ old('office_hours') }}" class="office-time">`,
+			expected: `<input type="time" name="office_hours" value="{{ old('office_hours') }}" class="office-time" min="08:00" max="18:00">`,
 		},
 	}
 
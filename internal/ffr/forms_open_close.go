@@ -9,17 +9,43 @@ import (
 // --- Open/Close ---
 // replaceFormOpen は Blade 内の Form::open([...]) を <form> に置換する。
 func replaceFormOpen(text string) string {
-	patterns := []string{
-		`(?s)\{\!\!\s*Form::open\(\s*\[\s*(.*?)\s*\]\s*\)\s*\!\!\}`,
-		`(?s)\{\{\s*Form::open\(\s*\[\s*(.*?)\s*\]\s*\)\s*\}\}`,
+	return replaceFormMethod(text, "open")
+}
+
+// handleFormOpen は Form::open()・Form::open([...])・Form::open($options) を処理する。
+func handleFormOpen(args string) (string, bool) {
+	params, ok := positionalArgs(args)
+	switch {
+	case !ok || len(params) > 1:
+		return "", false
+	case len(params) == 0:
+		return processFormOpen(""), true
 	}
-	for _, pattern := range patterns {
-		re := regexCache.GetRegex(pattern)
-		text = re.ReplaceAllStringFunc(text, func(match string) string {
-			return processFormOpen(re.FindStringSubmatch(match)[1])
-		})
+	return formOpenTag(params[0])
+}
+
+// handleFormModel は Form::model($model, [...]) を form タグにする。
+// モデルは Form::close までのフィールドの値に使う（formContext）。
+func handleFormModel(args string) (string, bool) {
+	params, ok := positionalArgs(args)
+	switch {
+	case !ok || len(params) == 0 || len(params) > 2:
+		return "", false
+	case len(params) == 1:
+		return processFormOpen(""), true
+	}
+	return formOpenTag(params[1])
+}
+
+// formOpenTag はオプションの引数（配列リテラル、または配列を返す式）から form タグを生成する。
+func formOpenTag(options *phpNode) (string, bool) {
+	switch {
+	case options.Kind == phpArray:
+		return processFormOpen(arrayContent(options)), true
+	case isLiteral(options):
+		return "", false
 	}
-	return text
+	return processFormOpenExpression(options), true
 }
 
 // processFormOpen は open のオプション（action/method/attrs）を解析して form タグを生成する。
@@ -30,121 +56,220 @@ func processFormOpen(content string) string {
 	return buildFormTag(action, method, extraAttrs)
 }
 
-// extractFormAction は route/url 指定から action を抽出する（route を優先）。
+// processFormOpenExpression は変数などの式で渡されたオプションから、実行時に値を読む form タグを生成する。
+// action・method・files 以外のキーは ComponentAttributeBag で属性として出力する。
+func processFormOpenExpression(options *phpNode) string {
+	o := options.operandSrc()
+	action := fmt.Sprintf("{{ isset(%[1]s['url']) ? url(%[1]s['url']) : (isset(%[1]s['route']) ? route(...(array) %[1]s['route']) : "+
+		"(isset(%[1]s['action']) ? action(...(array) %[1]s['action']) : '')) }}", o)
+	method, err := parsePHPExpr(fmt.Sprintf("%s['method'] ?? 'POST'", o))
+	if err != nil {
+		method = nil
+	}
+	extraAttrs := fmt.Sprintf(` @if(!empty(%[1]s['files'])) enctype="multipart/form-data" @endif`+
+		`{{ new \Illuminate\View\ComponentAttributeBag(\Illuminate\Support\Arr::except(%[1]s, ['url', 'route', 'action', 'method', 'files'])) }}`, o)
+	return buildFormTag(action, method, extraAttrs)
+}
+
+// extractFormAction は route/url/action 指定から action を抽出する（route、url、action の順に優先）。
 func extractFormAction(content string) string {
-	paramRouteStart := regexCache.GetRegex(`'route'\s*=>\s*\[\s*'([^']+)'\s*,\s*`)
-	if startMatch := paramRouteStart.FindStringSubmatchIndex(content); len(startMatch) > 3 {
-		routeName := content[startMatch[2]:startMatch[3]]
-		paramStart := startMatch[1]
-		params := extractRouteParamsBalanced(content[paramStart:])
-		if params != "" {
-			return fmt.Sprintf("{{ route('%s', %s) }}", routeName, params)
-		}
+	options := parseOptionsArray(content)
+	if route := options.arrayValue("route"); route != nil {
+		return formRouteAction(route)
 	}
-	arrayRouteRe := regexCache.GetRegex(`'route'\s*=>\s*\[\s*'([^']+)'\s*\]`)
-	if arrayMatches := arrayRouteRe.FindStringSubmatch(content); len(arrayMatches) > 1 {
-		return fmt.Sprintf("{{ route('%s') }}", arrayMatches[1])
-	}
-	simpleRouteRe := regexCache.GetRegex(`'route'\s*=>\s*'([^']+)'`)
-	if simpleMatches := simpleRouteRe.FindStringSubmatch(content); len(simpleMatches) > 1 {
-		return fmt.Sprintf("{{ route('%s') }}", simpleMatches[1])
-	}
-	return extractFormUrl(content)
-}
-
-// extractRouteParamsBalanced は route 配列引数をバランスした括弧で抽出する。
-func extractRouteParamsBalanced(content string) string {
-	var result strings.Builder
-	var bracketCount int
-	var inQuotes bool
-	var quoteChar rune
-	var escapeNext bool
-	for _, char := range content {
-		if escapeNext {
-			result.WriteRune(char)
-			escapeNext = false
-			continue
-		}
-		if char == '\\' && inQuotes {
-			result.WriteRune(char)
-			escapeNext = true
-			continue
-		}
-		if !inQuotes && (char == '"' || char == '\'') {
-			inQuotes = true
-			quoteChar = char
-		} else if inQuotes && char == quoteChar {
-			inQuotes = false
-			quoteChar = 0
+	url := options.arrayValue("url")
+	switch {
+	case url == nil:
+		if action := options.arrayValue("action"); action != nil {
+			return formControllerAction(action)
 		}
-		if !inQuotes {
-			switch char {
-			case '[':
-				bracketCount++
-			case ']':
-				bracketCount--
-				if bracketCount == 0 {
-					result.WriteRune(char)
-					return result.String()
-				}
-			}
-		}
-		result.WriteRune(char)
-	}
-	return ""
-}
-
-// extractFormUrl は open の url オプションを抽出する。
-func extractFormUrl(content string) string {
-	urlRe := regexCache.GetRegex(`'url'\s*=>\s*([^,\]]+)`)
-	if matches := urlRe.FindStringSubmatch(content); len(matches) > 1 {
-		urlVal := strings.TrimSpace(matches[1])
-		if strings.HasPrefix(urlVal, "route(") {
-			routeFuncRe := regexCache.GetRegex(`route\([^)]*(?:\([^)]*\)[^)]*)*\)`)
-			if routeMatch := routeFuncRe.FindString(content); routeMatch != "" {
-				return fmt.Sprintf("{{ %s }}", routeMatch)
-			}
-			return fmt.Sprintf("{{ %s }}", urlVal)
-		}
-		return urlVal
+		return ""
+	case url.Kind == phpString || url.Kind == phpNumber:
+		// リテラルの URL は Collective と同じく url() で完全な URL にする
+		return fmt.Sprintf("{{ url(%s) }}", url.Src)
 	}
-	return ""
+	return fmt.Sprintf("{{ %s }}", url.Src)
 }
 
-// extractFormMethod は HTTP メソッドを抽出する（既定は GET）。
-func extractFormMethod(content string) string {
-	methodRe := regexCache.GetRegex(`'method'\s*=>\s*'([^']+)'`)
-	if methodRe.MatchString(content) {
-		return methodRe.FindStringSubmatch(content)[1]
+// formRouteAction は route オプション（ルート名 / [ルート名, パラメータ...] / 式）を route() の呼び出しにする。
+func formRouteAction(route *phpNode) string {
+	if route.Kind != phpArray {
+		return fmt.Sprintf("{{ route(%s) }}", route.Src)
+	}
+	var args []string
+	for _, item := range route.Items {
+		args = append(args, item.Value.Src)
+	}
+	return fmt.Sprintf("{{ route(%s) }}", strings.Join(args, ", "))
+}
+
+// formControllerAction は action オプション（'Controller@method' / [Controller::class, 'method'] /
+// ['Controller@method', パラメータ...]）を action() の呼び出しにする。
+func formControllerAction(action *phpNode) string {
+	if action.Kind != phpArray || len(action.Items) == 0 {
+		return fmt.Sprintf("{{ action(%s) }}", action.Src)
+	}
+	var values []string
+	for _, item := range action.Items {
+		values = append(values, item.Value.Src)
 	}
-	return "GET"
+	// [Controller::class, 'method'] はコントローラとメソッドの組
+	if first := action.Items[0].Value; len(values) >= 2 && first.Kind == phpProperty && first.Value == "::" &&
+		strings.EqualFold(first.Name, "class") && isPlainString(action.Items[1].Value) {
+		values = append([]string{fmt.Sprintf("[%s, %s]", values[0], values[1])}, values[2:]...)
+	}
+	if len(values) == 1 {
+		return fmt.Sprintf("{{ action(%s) }}", values[0])
+	}
+	return fmt.Sprintf("{{ action(%s, [%s]) }}", values[0], strings.Join(values[1:], ", "))
+}
+
+// extractFormMethod は method オプションの式を返す（指定がなければ nil）。
+func extractFormMethod(content string) *phpNode {
+	return parseOptionsArray(content).arrayValue("method")
 }
 
 // extractFormAttributes は id/class/target などの追加属性を整形する。
+// files が真なら enctype="multipart/form-data" を先頭に出力する（指定された enctype より優先）。
 func extractFormAttributes(content string) string {
+	enctype := ""
+	files := parseOptionsArray(content).arrayValue("files")
+	switch {
+	case files == nil || isFalsyLiteral(files):
+	case isLiteral(files):
+		enctype = ` enctype="multipart/form-data"`
+	default:
+		enctype = fmt.Sprintf(` @if(%s) enctype="multipart/form-data" @endif`, files.Src)
+	}
 	attrProcessor := &AttributeProcessor{
-		Order: []string{"class", "id", "target"},
-		Patterns: map[string]string{
-			"target": `'target'\s*=>\s*'([^']+)'`,
-			"id":     `'id'\s*=>\s*'([^']+)'`,
-			"class":  `'class'\s*=>\s*'([^']+)'`,
+		Order: attributeOrder("form", []string{"class", "id", "target"}),
+		Values: map[string]attrValue{
+			"target": attrText,
+			"id":     attrText,
+			"class":  attrText,
 		},
+		Skip: []string{"method", "url", "route", "action", "files"},
+	}
+	if enctype != "" && isLiteral(files) {
+		attrProcessor.Skip = append(attrProcessor.Skip, "enctype")
 	}
-	return attrProcessor.ProcessAttributes(content)
+	return enctype + attrProcessor.ProcessAttributes(content)
 }
 
-// buildFormTag は method に応じて CSRF を付与しつつ form タグを構築する。
-func buildFormTag(action, method, extraAttrs string) string {
-	if strings.ToUpper(method) == "GET" {
-		return fmt.Sprintf(`<form action="%s" method="%s"%s>`, action, method, extraAttrs)
+// isFalsyLiteral は node が PHP で偽になるリテラル（false / null / 0 / 空文字 / '0'）かを返す。
+func isFalsyLiteral(node *phpNode) bool {
+	text, isText := node.stringLiteral()
+	return node.isConst("false") || node.isConst("null") || isText && (text == "" || text == "0") ||
+		node.Kind == phpNumber && strings.Trim(node.Value, "0.") == ""
+}
+
+// buildFormTag は form タグを構築する。ブラウザが送信できるのは GET / POST だけのため、
+// PUT / PATCH / DELETE 等は method="POST" とし、_method のフィールドで本来のメソッドを送る。
+// GET 以外には CSRF を付与する。method の指定がなければ Collective と同じく POST とし、
+// 式の場合は、実行時に同じ判定をする Blade にする。
+func buildFormTag(action string, method *phpNode, extraAttrs string) string {
+	var tagMethod string
+	var lines []string
+	switch {
+	case method == nil || method.isConst("null"):
+		tagMethod, lines = "POST", []string{csrfMarkup("POST")}
+	case method.Kind == phpString && !method.Interp:
+		name := normalizeFormMethod(method.Value)
+		tagMethod = formTagMethod(name)
+		lines = []string{csrfMarkup(name), methodFieldMarkup(phpSingleQuote(name), name)}
+	case method.Kind == phpTernary && method.Children[1] != nil && isPlainString(method.Children[1]) && isPlainString(method.Children[2]):
+		cond := method.Children[0].Src
+		a, b := normalizeFormMethod(method.Children[1].Value), normalizeFormMethod(method.Children[2].Value)
+		tagMethod = formTagMethod(a)
+		if formTagMethod(b) != tagMethod {
+			tagMethod = fmt.Sprintf("{{ %s ? %s : %s }}", cond, phpSingleQuote(formTagMethod(a)), phpSingleQuote(formTagMethod(b)))
+		}
+		lines = []string{
+			conditionalMarkup(cond, csrfMarkup(a), csrfMarkup(b)),
+			conditionalMarkup(cond, methodFieldMarkup(phpSingleQuote(a), a), methodFieldMarkup(phpSingleQuote(b), b)),
+		}
+	default:
+		upper := fmt.Sprintf("strtoupper(%s)", method.Src)
+		tagMethod = fmt.Sprintf("{{ %s === 'GET' ? 'GET' : 'POST' }}", upper)
+		lines = []string{
+			fmt.Sprintf("@unless(%s === 'GET')\n%s\n@endunless", upper, settings.CSRFField),
+			fmt.Sprintf("@unless(in_array(%s, ['GET', 'POST']))\n%s\n@endunless", upper, methodFieldMarkup(upper, "")),
+		}
+	}
+	tag := fmt.Sprintf(`<form action="%s" method="%s"%s>`, action, tagMethod, extraAttrs)
+	for _, line := range lines {
+		if line != "" {
+			tag += "\n" + line
+		}
+	}
+	return tag
+}
+
+// normalizeFormMethod はリテラルのメソッド名を大文字にする（空なら Collective と同じく POST）。
+func normalizeFormMethod(method string) string {
+	if method = strings.ToUpper(strings.TrimSpace(method)); method == "" {
+		return "POST"
 	}
-	return fmt.Sprintf(`<form action="%s" method="%s"%s>
-{{ csrf_field() }}`, action, method, extraAttrs)
+	return method
+}
+
+// formTagMethod は form タグの method 属性に書くメソッド（GET 以外は POST）を返す。
+func formTagMethod(method string) string {
+	if method == "GET" {
+		return "GET"
+	}
+	return "POST"
+}
+
+// csrfMarkup は GET 以外のメソッドに付ける CSRF のマークアップを返す。
+func csrfMarkup(method string) string {
+	if method == "GET" {
+		return ""
+	}
+	return settings.CSRFField
+}
+
+// methodFieldMarkup は _method のフィールドを返す。method が GET / POST なら空文字。
+// expr はメソッド名の PHP の式、method はリテラルのメソッド名（式の場合は空文字）。
+func methodFieldMarkup(expr, method string) string {
+	if method == "GET" || method == "POST" {
+		return ""
+	}
+	if settings.MethodField == "input" {
+		value := method
+		if value == "" {
+			value = fmt.Sprintf("{{ %s }}", expr)
+		}
+		return fmt.Sprintf(`<input type="hidden" name="_method" value="%s">`, value)
+	}
+	return fmt.Sprintf("@method(%s)", expr)
+}
+
+// conditionalMarkup は条件 cond の真偽で出し分けるマークアップを返す。
+func conditionalMarkup(cond, whenTrue, whenFalse string) string {
+	switch {
+	case whenTrue == whenFalse:
+		return whenTrue
+	case whenFalse == "":
+		return fmt.Sprintf("@if(%s)\n%s\n@endif", cond, whenTrue)
+	case whenTrue == "":
+		return fmt.Sprintf("@unless(%s)\n%s\n@endunless", cond, whenFalse)
+	}
+	return fmt.Sprintf("@if(%s)\n%s\n@else\n%s\n@endif", cond, whenTrue, whenFalse)
+}
+
+// isPlainString は node が変数展開を含まない文字列リテラルかを返す。
+func isPlainString(node *phpNode) bool {
+	_, ok := node.stringLiteral()
+	return ok
 }
 
 // replaceFormClose は Form::close() を </form> に置換する。
 func replaceFormClose(text string) string {
-	return ProcessBladePatterns(text, `Form::close\(\)`, func(content string) string {
-		return "</form>"
-	})
+	return replaceFormMethod(text, "close")
+}
+
+// handleFormClose は引数のない Form::close() を </form> にする。
+func handleFormClose(args string) (string, bool) {
+	return "</form>", args == ""
 }
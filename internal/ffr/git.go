@@ -0,0 +1,152 @@
+// git.go: git リポジトリの変更状況に基づく対象ファイルの絞り込み（--git-changed / --git-staged）。
+package ffr
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// usesGit は git の変更状況で対象を絞り込むかを返す。
+func (c *ReplacementConfig) usesGit() bool {
+	return c.GitChanged != "" || c.GitStaged
+}
+
+// validateGitRef は --git-changed の ref が git のオプションとして解釈されないかを確認する。
+func validateGitRef(ref string) error {
+	if strings.HasPrefix(ref, "-") {
+		return fmt.Errorf("--git-changed の ref は - で始められません: %q", ref)
+	}
+	return nil
+}
+
+// runGit は dir で git コマンドを実行し、標準出力を返す。
+func runGit(dir string, args ...string) ([]byte, error) {
+	cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("git %s: %s", strings.Join(args, " "), msg)
+		}
+		return nil, fmt.Errorf("git %s: %v", strings.Join(args, " "), err)
+	}
+	return out, nil
+}
+
+// gitPathList は -z 形式（NUL 区切り）のパス一覧を分割する。
+func gitPathList(out []byte) []string {
+	var paths []string
+	for _, p := range strings.Split(string(out), "\x00") {
+		if p != "" {
+			paths = append(paths, p)
+		}
+	}
+	return paths
+}
+
+// gitBaseDir は git コマンドを実行するディレクトリ（対象ディレクトリ、またはファイルの親）を返す。
+func gitBaseDir(config *ReplacementConfig) (string, error) {
+	dir := config.TargetPath
+	if config.IsFile {
+		dir = filepath.Dir(dir)
+	}
+	return filepath.Abs(dir)
+}
+
+// selectGitFiles は --git-changed / --git-staged の対象となるファイルを git に問い合わせ、
+// config.gitPaths に設定する。パスは対象ディレクトリ配下のものに限られる。
+func selectGitFiles(config *ReplacementConfig) error {
+	if err := validateGitRef(config.GitChanged); err != nil {
+		return err
+	}
+	dir, err := gitBaseDir(config)
+	if err != nil {
+		return err
+	}
+	if _, err := runGit(dir, "rev-parse", "--show-toplevel"); err != nil {
+		return fmt.Errorf("'%s' は git リポジトリ内ではありません: %v", config.TargetPath, err)
+	}
+
+	// --relative で dir からの相対パス（dir 配下のみ）を得る。削除されたファイルは除く
+	args := []string{"diff", "--name-only", "-z", "--relative", "--diff-filter=ACMR"}
+	if config.GitStaged {
+		args = append(args, "--cached")
+	} else {
+		// <ref> の先端ではなく分岐点と比べ、<ref> 側にだけ入った変更を対象にしない。
+		// コミットと作業ツリーを比べるため、未コミットの変更も含まれる
+		base, err := runGit(dir, "merge-base", config.GitChanged, "HEAD")
+		if err != nil {
+			return fmt.Errorf("'%s' と HEAD の分岐点を求められません: %v", config.GitChanged, err)
+		}
+		args = append(args, strings.TrimSpace(string(base)), "--")
+	}
+	out, err := runGit(dir, args...)
+	if err != nil {
+		return err
+	}
+
+	config.gitPaths = make(map[string]bool)
+	for _, rel := range gitPathList(out) {
+		path := filepath.Join(dir, filepath.FromSlash(rel))
+		if hasTargetSuffix(path) {
+			config.gitPaths[path] = true
+		}
+	}
+	return nil
+}
+
+// dirtyGitFiles は書き換えると未コミットの変更と混ざってしまうファイルを返す。
+// --git-staged ではステージ済みファイルのうち未ステージの変更があるもの、
+// それ以外では対象ディレクトリ配下のコミットされていない変更（未追跡ファイルを除く）が対象。
+func dirtyGitFiles(config *ReplacementConfig) ([]string, error) {
+	dir, err := gitBaseDir(config)
+	if err != nil {
+		return nil, err
+	}
+	var dirty []string
+	if config.GitStaged {
+		out, err := runGit(dir, "diff", "--name-only", "-z", "--relative")
+		if err != nil {
+			return nil, err
+		}
+		for _, rel := range gitPathList(out) {
+			if config.gitPaths[filepath.Join(dir, filepath.FromSlash(rel))] {
+				dirty = append(dirty, rel)
+			}
+		}
+	} else {
+		out, err := runGit(dir, "diff", "HEAD", "--name-only", "-z", "--relative")
+		if err != nil {
+			return nil, err
+		}
+		dirty = gitPathList(out)
+	}
+	sort.Strings(dirty)
+	return dirty, nil
+}
+
+// checkCleanWorkingTree は未コミットの変更がある場合にエラーを返す（--force で無視できる）。
+func checkCleanWorkingTree(config *ReplacementConfig) error {
+	dirty, err := dirtyGitFiles(config)
+	if err != nil {
+		return err
+	}
+	if len(dirty) == 0 {
+		return nil
+	}
+	return fmt.Errorf("作業ツリーにコミットされていない変更があります（変換結果と混ざるため中止しました。--force で続行できます）:\n  %s", strings.Join(dirty, "\n  "))
+}
+
+// isGitSelected は --git-changed / --git-staged で選ばれたファイルかを返す（絞り込みなしなら常に true）。
+func isGitSelected(config *ReplacementConfig, path string) bool {
+	if config.gitPaths == nil {
+		return true
+	}
+	abs, err := filepath.Abs(path)
+	return err == nil && config.gitPaths[abs]
+}
@@ -0,0 +1,210 @@
+package ffr
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// setupGitTest は Form:: を含む Blade ファイルをコミット済みの git リポジトリを作成する。
+func setupGitTest(t *testing.T, files ...string) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not available")
+	}
+	dir := t.TempDir()
+	git(t, dir, "init", "-q")
+	for _, name := range files {
+		writeBlade(t, dir, name, "{{ Form::text('"+name+"') }}\n")
+	}
+	git(t, dir, "add", "-A")
+	git(t, dir, "commit", "-q", "-m", "base")
+	return dir
+}
+
+// git はテスト用リポジトリで git コマンドを実行し、標準出力を返す。
+func git(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+	cmd.Env = append(os.Environ(),
+		"GIT_AUTHOR_NAME=test", "GIT_AUTHOR_EMAIL=test@example.com",
+		"GIT_COMMITTER_NAME=test", "GIT_COMMITTER_EMAIL=test@example.com")
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %v: %v\n%s", args, err, out)
+	}
+	return strings.TrimSpace(string(out))
+}
+
+func writeBlade(t *testing.T, dir, name, content string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write %s: %v", name, err)
+	}
+}
+
+// convertedFiles は Form:: が変換されたファイル名を返す。
+func convertedFiles(t *testing.T, dir string, names ...string) []string {
+	t.Helper()
+	var converted []string
+	for _, name := range names {
+		hasFacade, err := containsFormFacade(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatalf("Failed to read %s: %v", name, err)
+		}
+		if !hasFacade {
+			converted = append(converted, name)
+		}
+	}
+	return converted
+}
+
+func TestGitChangedSelectsFilesChangedSinceRef(t *testing.T) {
+	useSettings(t, defaultSettings())
+	dir := setupGitTest(t, "a.blade.php", "b.blade.php")
+	base := git(t, dir, "rev-parse", "HEAD")
+	writeBlade(t, dir, "b.blade.php", "<div>\n{{ Form::text('b') }}\n</div>\n")
+	writeBlade(t, dir, "c.blade.php", "{{ Form::text('c') }}\n")
+	git(t, dir, "add", "-A")
+	git(t, dir, "commit", "-q", "-m", "feature")
+
+	if code := Run([]string{"form-facade-replacer", "--git-changed", base, dir}); code != 0 {
+		t.Fatalf("Run() = %d, want 0", code)
+	}
+	converted := convertedFiles(t, dir, "a.blade.php", "b.blade.php", "c.blade.php")
+	if strings.Join(converted, ",") != "b.blade.php,c.blade.php" {
+		t.Errorf("converted = %v, want [b.blade.php c.blade.php]", converted)
+	}
+}
+
+func TestGitChangedComparesWithMergeBase(t *testing.T) {
+	useSettings(t, defaultSettings())
+	dir := setupGitTest(t, "a.blade.php", "b.blade.php", "c.blade.php")
+	git(t, dir, "branch", "main-line")
+	writeBlade(t, dir, "b.blade.php", "<div>\n{{ Form::text('b') }}\n</div>\n")
+	git(t, dir, "commit", "-q", "-am", "feature")
+
+	// 分岐後に main-line 側だけで変更された a.blade.php は対象にしない
+	git(t, dir, "checkout", "-q", "main-line")
+	writeBlade(t, dir, "a.blade.php", "<p>{{ Form::text('a') }}</p>\n")
+	git(t, dir, "commit", "-q", "-am", "main")
+	git(t, dir, "checkout", "-q", "-")
+
+	// 未コミットの変更は対象に含める
+	writeBlade(t, dir, "c.blade.php", "<p>{{ Form::text('c') }}</p>\n")
+
+	if code := Run([]string{"form-facade-replacer", "--force", "--git-changed=main-line", dir}); code != 0 {
+		t.Fatalf("Run() = %d, want 0", code)
+	}
+	converted := convertedFiles(t, dir, "a.blade.php", "b.blade.php", "c.blade.php")
+	if strings.Join(converted, ",") != "b.blade.php,c.blade.php" {
+		t.Errorf("converted = %v, want [b.blade.php c.blade.php]", converted)
+	}
+}
+
+func TestGitChangedRefusesDirtyWorkingTree(t *testing.T) {
+	useSettings(t, defaultSettings())
+	dir := setupGitTest(t, "a.blade.php", "b.blade.php")
+	writeBlade(t, dir, "a.blade.php", "{{ Form::text('edited') }}\n")
+
+	if code := Run([]string{"form-facade-replacer", "--git-changed=HEAD", dir}); code != 1 {
+		t.Fatalf("Run() = %d, want 1", code)
+	}
+	if converted := convertedFiles(t, dir, "a.blade.php"); len(converted) != 0 {
+		t.Error("files must not be modified on a dirty working tree")
+	}
+
+	// dry-run は書き換えないため実行できる
+	if code := Run([]string{"form-facade-replacer", "--dry-run", "--git-changed=HEAD", dir}); code != 0 {
+		t.Errorf("Run(--dry-run) = %d, want 0", code)
+	}
+
+	if code := Run([]string{"form-facade-replacer", "--force", "--git-changed=HEAD", dir}); code != 0 {
+		t.Fatalf("Run(--force) = %d, want 0", code)
+	}
+	converted := convertedFiles(t, dir, "a.blade.php", "b.blade.php")
+	if strings.Join(converted, ",") != "a.blade.php" {
+		t.Errorf("converted = %v, want [a.blade.php]", converted)
+	}
+}
+
+func TestGitStagedSelectsIndexedFiles(t *testing.T) {
+	useSettings(t, defaultSettings())
+	dir := setupGitTest(t, "a.blade.php", "b.blade.php", "c.blade.php")
+	writeBlade(t, dir, "a.blade.php", "{{ Form::text('staged') }}\n")
+	git(t, dir, "add", "a.blade.php")
+	// 未ステージの変更はコミット対象ではないため選ばれない
+	writeBlade(t, dir, "b.blade.php", "{{ Form::text('unstaged') }}\n")
+
+	if code := Run([]string{"form-facade-replacer", "--git-staged", dir}); code != 0 {
+		t.Fatalf("Run() = %d, want 0", code)
+	}
+	converted := convertedFiles(t, dir, "a.blade.php", "b.blade.php", "c.blade.php")
+	if strings.Join(converted, ",") != "a.blade.php" {
+		t.Errorf("converted = %v, want [a.blade.php]", converted)
+	}
+
+	// ステージ後にさらに編集されたファイルは書き換えを拒否する
+	writeBlade(t, dir, "c.blade.php", "{{ Form::text('c1') }}\n")
+	git(t, dir, "add", "c.blade.php")
+	writeBlade(t, dir, "c.blade.php", "{{ Form::text('c2') }}\n")
+	if code := Run([]string{"form-facade-replacer", "--git-staged", dir}); code != 1 {
+		t.Errorf("Run() = %d, want 1", code)
+	}
+	// pre-commit フックでのチェックは作業ツリーの状態に関係なく実行できる
+	if code := Run([]string{"form-facade-replacer", "--check", "--git-staged", dir}); code != exitCheckChanges {
+		t.Errorf("Run(--check) = %d, want %d", code, exitCheckChanges)
+	}
+}
+
+func TestParseArgsGit(t *testing.T) {
+	config := newReplacementConfig()
+	if _, err := parseArgs([]string{"--git-changed=origin/main", "--force", "views"}, config); err != nil {
+		t.Fatalf("parseArgs() error: %v", err)
+	}
+	if config.GitChanged != "origin/main" || !config.Force || !config.usesGit() {
+		t.Errorf("unexpected config: %+v", config)
+	}
+
+	if _, err := parseArgs([]string{"--git-changed", "main", "--git-staged", "views"}, newReplacementConfig()); err == nil {
+		t.Error("parseArgs() should reject --git-changed with --git-staged")
+	}
+	if _, err := parseArgs([]string{"--git-staged", "-"}, newReplacementConfig()); err == nil {
+		t.Error("parseArgs() should reject git options in stdin mode")
+	}
+	if _, err := parseArgs([]string{"--git-changed", "--output=/tmp/x", "views"}, newReplacementConfig()); err == nil {
+		t.Error("parseArgs() should reject a ref that starts with -")
+	}
+}
+
+func TestGitChangedRejectsOptionLikeRef(t *testing.T) {
+	dir := setupGitTest(t, "a.blade.php")
+	output := filepath.Join(t.TempDir(), "x")
+	config := newReplacementConfig()
+	config.TargetPath = dir
+	config.GitChanged = "--output=" + output
+	if err := selectGitFiles(config); err == nil {
+		t.Error("selectGitFiles() should reject a ref that starts with -")
+	}
+	if _, err := os.Stat(output); !os.IsNotExist(err) {
+		t.Errorf("git wrote %s", output)
+	}
+}
+
+func TestGitOptionsOutsideRepository(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not available")
+	}
+	dir := t.TempDir()
+	if findGitRoot(dir) != "" {
+		t.Skip("temporary directory is inside a git repository")
+	}
+	config := newReplacementConfig()
+	config.TargetPath = dir
+	config.GitStaged = true
+	if err := selectGitFiles(config); err == nil {
+		t.Error("selectGitFiles() should fail outside a git repository")
+	}
+}
@@ -9,22 +9,7 @@ import (
 // --- Number ---
 // replaceFormNumber は Blade 内の Form::number(...) を HTML に置換する。
 func replaceFormNumber(text string) string {
-	patterns := []string{
-		`(?s)\{\{\s*Form::number\(\s*(.*?)\s*\)\s*\}\}`,
-		`(?s)\{\!\!\s*Form::number\(\s*(.*?)\s*\)\s*\!\!\}`,
-	}
-	for _, pattern := range patterns {
-		re := regexCache.GetRegex(pattern)
-		text = re.ReplaceAllStringFunc(text, func(match string) string {
-			fullMatch := re.FindStringSubmatch(match)
-			if len(fullMatch) > 1 {
-				params := extractParamsBalanced(fullMatch[1])
-				return processFormNumber(params)
-			}
-			return match
-		})
-	}
-	return text
+	return replaceFormMethod(text, "number")
 }
 
 // processFormNumber は number の属性・value を解決して最終HTMLを生成する。
@@ -38,15 +23,16 @@ func processFormNumber(params []string) string {
 		value = params[1]
 	}
 	attrProcessor := &AttributeProcessor{
-		Order: []string{"placeholder", "class", "id", "min", "max", "step"},
-		Patterns: map[string]string{
-			"placeholder": `'placeholder'\s*=>\s*'([^']+)'`,
-			"class":       `'class'\s*=>\s*'([^']+)'`,
-			"id":          `'id'\s*=>\s*'([^']+)'`,
-			"min":         `'min'\s*=>\s*(\d+)`,
-			"max":         `'max'\s*=>\s*(\d+)`,
-			"step":        `'step'\s*=>\s*(\d+(?:\.\d+)?)`,
+		Order: attributeOrder("number", []string{"placeholder", "class", "id", "min", "max", "step"}),
+		Values: map[string]attrValue{
+			"placeholder": attrText,
+			"class":       attrText,
+			"id":          attrText,
+			"min":         attrText,
+			"max":         attrText,
+			"step":        attrText,
 		},
+		Skip: []string{"type", "name", "value"},
 	}
 	extraAttrs := ""
 	if len(params) > 2 {
@@ -66,20 +52,5 @@ func processFormNumber(params []string) string {
 // --- Range ---
 // replaceFormRange は Blade 内の Form::range(...) を HTML に置換する。
 func replaceFormRange(text string) string {
-	patterns := []string{
-		`(?s)\{\!\!\s*Form::range\(\s*(.*?)\s*\)\s*\!\!\}`,
-		`(?s)\{\{\s*Form::range\(\s*(.*?)\s*\)\s*\}\}`,
-	}
-	for _, pattern := range patterns {
-		re := regexCache.GetRegex(pattern)
-		text = re.ReplaceAllStringFunc(text, func(match string) string {
-			fullMatch := re.FindStringSubmatch(match)
-			if len(fullMatch) > 1 {
-				params := extractParamsBalanced(fullMatch[1])
-				return processFormInput("range", params)
-			}
-			return match
-		})
-	}
-	return text
+	return replaceFormMethod(text, "range")
 }
@@ -90,7 +90,7 @@ func TestFormIntegration(t *testing.T) {
 </select>
             </div>
             <div class="form-group">
-                <input type="checkbox" name="newsletter" value="{{ 1 }}" @if(old('newsletter')) checked @endif class="form-check-input">
+                <input type="checkbox" name="newsletter" value="1" @if(old('newsletter')) checked @endif class="form-check-input">
                 <label for="newsletter">{!! 'Subscribe to newsletter' !!}</label>
             </div>
             <input type="hidden" name="source" value="{{ 'web' }}">
@@ -112,15 +112,15 @@ func TestFormIntegration(t *testing.T) {
     {!! Form::checkbox('urgent', 1, false, ['id' => 'urgent-check']) !!}
     {{ Form::select('department', $departments, null, ['class' => 'form-select']) }}
 {{ Form::close() }}`,
-			expected: `<form action="'/contact'" method="POST">
+			expected: `<form action="{{ url('/contact') }}" method="POST">
 {{ csrf_field() }}
     <input type="text" name="name" value="{{ $user->name }}">
     <input type="hidden" name="user_id" value="{{ $user->id }}">
     <textarea name="message" placeholder="Your message">{{ old('message') }}</textarea>
-    <input type="checkbox" name="urgent" value="{{ 1 }}" @if(false) checked @endif id="urgent-check">
+    <input type="checkbox" name="urgent" value="1" id="urgent-check">
     <select name="department" class="form-select">
 @foreach($departments as $key => $value)
-<option value="{{ $key }}" @if($key == null) selected @endif>{{ $value }}</option>
+<option value="{{ $key }}">{{ $value }}</option>
 @endforeach
 </select>
 </form>`,
@@ -136,8 +136,8 @@ func TestFormIntegration(t *testing.T) {
 {!! Form::close() !!}`,
 			expected: `<form action="{{ route('survey.store') }}" method="POST">
 {{ csrf_field() }}
-    <input type="checkbox" name="interests[]" value="{{ tech }}" @if(in_array(tech, (array)old('interests'))) checked @endif class="interest-check">
-    <input type="checkbox" name="interests[]" value="{{ sports }}" @if(in_array(sports, (array)old('interests'))) checked @endif class="interest-check">
+    <input type="checkbox" name="interests[]" value="tech" @if(in_array('tech', (array)old('interests'))) checked @endif class="interest-check">
+    <input type="checkbox" name="interests[]" value="sports" @if(in_array('sports', (array)old('interests'))) checked @endif class="interest-check">
     <input type="text" name="skills[]" value="" class="skill-input">
     <input type="hidden" name="responses[0][question_id]" value="{{ is_array(1) ? implode(',', 1) : 1 }}">
     <textarea name="responses[0][answer]" rows="3"></textarea>
@@ -155,7 +155,7 @@ func TestFormIntegration(t *testing.T) {
     {!! Form::textarea('bio', old('bio'), ['rows' => 4, 'placeholder' => 'Tell us about yourself']) !!}
     {!! Form::submit('Update Profile', ['class' => 'btn btn-primary']) !!}
 {!! Form::close() !!}`,
-			expected: `<form action="{{ route('profile.update') }}" method="POST">
+			expected: `<form action="{{ route('profile.update') }}" method="POST" enctype="multipart/form-data">
 {{ csrf_field() }}
     <label for="name">{!! 'Full Name' !!}</label>
     <input type="text" name="name" value="{{ old('name') }}" class="form-control">
@@ -287,7 +287,8 @@ func TestDirectoryProcessingIntegration(t *testing.T) {
 	}
 
 	expected := map[string]string{
-		"form1.blade.php": `<form action="'/test'" method="GET">
+		"form1.blade.php": `<form action="{{ url('/test') }}" method="POST">
+{{ csrf_field() }}
 <input type="text" name="name" value="">
 </form>`,
 		"form2.blade.php": `<form action="" method="POST">
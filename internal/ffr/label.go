@@ -6,22 +6,7 @@ import "fmt"
 // --- Label ---
 // replaceFormLabel は Blade 内の Form::label(...) を HTML に置換する。
 func replaceFormLabel(text string) string {
-	patterns := []string{
-		`(?s)\{\!\!\s*Form::label\(\s*(.*?)\s*\)\s*\!\!\}`,
-		`(?s)\{\{\s*Form::label\(\s*(.*?)\s*\)\s*\}\}`,
-	}
-	for _, pattern := range patterns {
-		re := regexCache.GetRegex(pattern)
-		text = re.ReplaceAllStringFunc(text, func(match string) string {
-			fullMatch := re.FindStringSubmatch(match)
-			if len(fullMatch) > 1 {
-				params := extractParamsBalanced(fullMatch[1])
-				return processFormLabel(params)
-			}
-			return match
-		})
-	}
-	return text
+	return replaceFormMethod(text, "label")
 }
 
 // processFormLabel は for/表示テキスト/追加属性を解決して最終HTMLを生成する。
@@ -29,28 +14,26 @@ func processFormLabel(params []string) string {
 	if len(params) < 1 {
 		return ""
 	}
-	name := ProcessFieldName(params[0])
-	forAttr := name
-	textParam := ""
-	if len(params) == 1 {
-		textParam = fmt.Sprintf("'%s'", name)
-	} else {
+	forAttr := ProcessFieldName(params[0])
+	// 表示テキストを省略した場合は name の式をそのまま表示する（変数展開・連結もそのまま評価される）
+	textParam := params[0]
+	if len(params) > 1 {
 		textParam = params[1]
 	}
 	attrProcessor := &AttributeProcessor{
-		Order: []string{"class", "id", "style"},
-		Patterns: map[string]string{
-			"class": `'class'\s*=>\s*'([^']+)'`,
-			"id":    `'id'\s*=>\s*'([^']+)'`,
-			"style": `'style'\s*=>\s*'([^']+)'`,
+		Order: attributeOrder("label", []string{"class", "id", "style"}),
+		Values: map[string]attrValue{
+			"class": attrText,
+			"id":    attrText,
+			"style": attrText,
 		},
+		Skip: []string{"for"},
 	}
 	extraAttrs := ""
 	if len(params) > 2 {
 		attrs := params[2]
-		forRe := regexCache.GetRegex(`'for'\s*=>\s*'([^']+)'`)
-		if forRe.MatchString(attrs) {
-			forAttr = forRe.FindStringSubmatch(attrs)[1]
+		if value, ok := attributeValue(arrayOption(parseOptionsArray(attrs), "for"), attrText); ok {
+			forAttr = value
 		}
 		extraAttrs = attrProcessor.ProcessAttributes(attrs)
 	}
@@ -0,0 +1,470 @@
+// locator.go: テンプレートを1回走査して Form:: の呼び出しを見つけ、メソッドごとのハンドラへ振り分ける。
+package ffr
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// formHandler は Form::メソッド( ... ) の引数（括弧の内側）から HTML を生成する。
+// ok=false の場合は変換せず、元の記述をそのまま残す。
+type formHandler func(args string) (html string, ok bool)
+
+// formHandlers はメソッド名（小文字）ごとの変換ハンドラ。
+var formHandlers = map[string]formHandler{
+	"open":        handleFormOpen,
+	"model":       handleFormModel,
+	"close":       handleFormClose,
+	"hidden":      paramsHandler(processFormHidden),
+	"button":      handleFormButton,
+	"textarea":    paramsHandler(processFormTextarea),
+	"label":       paramsHandler(processFormLabel),
+	"text":        inputHandler("text"),
+	"input":       paramsHandler(processFormInputDynamic),
+	"number":      paramsHandler(processFormNumber),
+	"select":      paramsHandler(processFormSelect),
+	"selectrange": handleFormSelectRange,
+	"selectyear":  handleFormSelectRange,
+	"selectmonth": handleFormSelectMonth,
+	"checkbox":    paramsHandler(processFormCheckbox),
+	"submit":      paramsHandler(processFormSubmit),
+	"file":        paramsHandler(processFormFile),
+	"email":       inputHandler("email"),
+	"password":    paramsHandler(processFormPassword),
+	"url":         inputHandler("url"),
+	"tel":         inputHandler("tel"),
+	"search":      inputHandler("search"),
+	"date":        inputHandler("date"),
+	"time":        inputHandler("time"),
+	"datetime":    inputHandler("datetime-local"),
+	"range":       inputHandler("range"),
+	"color":       inputHandler("color"),
+	"radio":       paramsHandler(processFormRadio),
+}
+
+// formParam は Collective のメソッドの引数1つ（defaultValue が空なら省略できない）。
+type formParam struct {
+	name         string
+	defaultValue string
+}
+
+// valueParams は name, value, options を取る input 系メソッドの引数
+var valueParams = []formParam{{"name", ""}, {"value", "null"}, {"options", "[]"}}
+
+// rangeParams は selectRange / selectYear の引数
+var rangeParams = []formParam{{"name", ""}, {"begin", ""}, {"end", ""}, {"selected", "null"}, {"options", "[]"}}
+
+// formParams はメソッド名（小文字）ごとの Collective の引数の並び。名前付き引数を位置引数に並べ替えるために使う。
+var formParams = map[string][]formParam{
+	"open":     {{"options", "[]"}},
+	"model":    {{"model", ""}, {"options", "[]"}},
+	"close":    {},
+	"hidden":   valueParams,
+	"button":   {{"value", "null"}, {"options", "[]"}},
+	"textarea": valueParams,
+	"label":    {{"name", ""}, {"value", "null"}, {"options", "[]"}, {"escape_html", "true"}},
+	"text":     valueParams,
+	"input":    {{"type", ""}, {"name", ""}, {"value", "null"}, {"options", "[]"}},
+	"number":   valueParams,
+	"select": {{"name", ""}, {"list", "[]"}, {"selected", "null"}, {"selectAttributes", "[]"},
+		{"optionsAttributes", "[]"}, {"optgroupsAttributes", "[]"}},
+	"selectrange": rangeParams,
+	"selectyear":  rangeParams,
+	"selectmonth": {{"name", ""}, {"selected", "null"}, {"options", "[]"}, {"format", "'%B'"}},
+	"checkbox":    {{"name", ""}, {"value", "1"}, {"checked", "null"}, {"options", "[]"}},
+	"submit":      {{"value", "null"}, {"options", "[]"}},
+	"file":        {{"name", ""}, {"options", "[]"}},
+	"email":       valueParams,
+	"password":    {{"name", ""}, {"options", "[]"}},
+	"url":         valueParams,
+	"tel":         valueParams,
+	"search":      valueParams,
+	"date":        valueParams,
+	"time":        valueParams,
+	"datetime":    valueParams,
+	"range":       valueParams,
+	"color":       valueParams,
+	"radio":       {{"name", ""}, {"value", "null"}, {"checked", "null"}, {"options", "[]"}},
+}
+
+// positionalSource は名前付き引数（name: 'email'）を含む引数リストを、メソッドの引数の並びに
+// 従った位置引数のソースに書き換える。間の省略された引数には既定値を補う。
+// 名前付き引数がなければ args をそのまま返し、並べ替えられない場合は ok=false。
+func positionalSource(method, args string) (string, bool) {
+	parsed, err := parsePHPArgs(args)
+	if err != nil {
+		return args, true
+	}
+	named := false
+	for _, arg := range parsed {
+		named = named || arg.Name != ""
+	}
+	if !named {
+		return args, true
+	}
+	params, known := formParams[method]
+	if !known {
+		return "", false
+	}
+	values := make([]string, len(params))
+	last := -1
+	for i, arg := range parsed {
+		index := i
+		if arg.Name != "" {
+			index = -1
+			for j, param := range params {
+				if param.name == arg.Name {
+					index = j
+				}
+			}
+		}
+		if arg.Spread || index < 0 || index >= len(params) || values[index] != "" {
+			return "", false
+		}
+		values[index] = arg.Value.Src
+		if index > last {
+			last = index
+		}
+	}
+	for i := 0; i <= last; i++ {
+		if values[i] == "" {
+			if params[i].defaultValue == "" {
+				return "", false
+			}
+			values[i] = params[i].defaultValue
+		}
+	}
+	return strings.Join(values[:last+1], ", "), true
+}
+
+// callHandler は引数を位置引数に揃え、モデルや old() の値を補ってからメソッドのハンドラを呼び出す。
+func callHandler(ctx *formContext, method string, handler formHandler, args string) (string, bool) {
+	args, ok := positionalSource(method, args)
+	if !ok {
+		return "", false
+	}
+	html, ok := handler(ctx.bindValue(method, args))
+	if ok {
+		ctx.track(method, args)
+	}
+	return html, ok
+}
+
+// paramsHandler は引数を PHP の式として解析し、各引数の記述を process に渡すハンドラを返す。
+// 解析できない場合や process が空文字を返す（引数が足りない）場合は変換しない。
+func paramsHandler(process func(params []string) string) formHandler {
+	return func(args string) (string, bool) {
+		nodes, ok := positionalArgs(args)
+		if !ok {
+			return "", false
+		}
+		params := make([]string, len(nodes))
+		for i, node := range nodes {
+			params[i] = node.Src
+		}
+		html := process(params)
+		return html, html != ""
+	}
+}
+
+// positionalArgs は引数を解析し、位置引数の構文木を返す。
+// 名前付き引数・スプレッド引数を含む場合は ok=false。
+func positionalArgs(args string) ([]*phpNode, bool) {
+	parsed, err := parsePHPArgs(args)
+	if err != nil {
+		return nil, false
+	}
+	nodes := make([]*phpNode, len(parsed))
+	for i, arg := range parsed {
+		if arg.Name != "" || arg.Spread {
+			return nil, false
+		}
+		nodes[i] = arg.Value
+	}
+	return nodes, true
+}
+
+// arrayContent は配列リテラル（[...] / array(...)）の括弧の内側を返す。
+func arrayContent(array *phpNode) string {
+	src := array.Src
+	if array.Long {
+		src = src[strings.IndexByte(src, '('):]
+	}
+	return strings.TrimSpace(src[1 : len(src)-1])
+}
+
+// inputHandler は processFormInput で指定 type の input を生成するハンドラを返す。
+func inputHandler(inputType string) formHandler {
+	return paramsHandler(func(params []string) string {
+		return processFormInput(inputType, params)
+	})
+}
+
+// conversionWarning は変換時に見つかった、変換しなかった Form:: の呼び出し。
+// 位置は変換前のテキストでの1始まりの行・桁（Form:: の先頭）。
+type conversionWarning struct {
+	Line    int
+	Column  int
+	Method  string
+	Message string
+	Skipped bool // コメント・@verbatim 等、変換対象外の領域にあるため変換しなかった
+}
+
+// label は表示用の種別（警告 / スキップ）を返す。
+func (w conversionWarning) label() string {
+	if w.Skipped {
+		return "スキップ"
+	}
+	return "警告"
+}
+
+// formCall はエコー内の Form:: 呼び出し1件分の位置（テキスト全体でのオフセット）。
+type formCall struct {
+	facade     int    // ファサードの参照（Form:: / \Form:: 等）の位置
+	method     string // メソッド名
+	argsStart  int    // "(" の直後
+	argsEnd    int    // 対応する ")" の位置
+	closeFound bool   // 対応する ")" が見つかったか
+	echoClosed bool   // ")" の後がエコーの終わりまで空白だけか
+}
+
+// convertTemplate はテンプレート内の Form:: 呼び出しをすべて変換し、変換しなかった呼び出しの警告を返す。
+func convertTemplate(text string) (string, []conversionWarning) {
+	converted, warnings, _ := convertTemplateMapped(text)
+	return converted, warnings
+}
+
+// convertTemplateMapped は convertTemplate に加えて、変換後の位置から変換前の位置を求める対応表を返す。
+func convertTemplateMapped(text string) (string, []conversionWarning, sourceMap) {
+	return replaceFormCalls(text, func(string) bool { return true })
+}
+
+// replaceFormMethod は指定メソッドの Form:: 呼び出しだけを変換する。
+func replaceFormMethod(text, method string) string {
+	converted, _, _ := replaceFormCalls(text, func(m string) bool { return m == method })
+	return converted
+}
+
+// sourceSegment は変換前のテキストの [origStart, origEnd) が、変換後の [outStart, outEnd) になったことを表す。
+type sourceSegment struct {
+	origStart, origEnd int
+	outStart, outEnd   int
+	copied             bool // 変換せずにそのまま写した領域か
+}
+
+// sourceMap は変換後のテキストの位置を変換前のテキストの位置に対応づける（Blade の領域ごと）。
+type sourceMap []sourceSegment
+
+// originalOffset は変換後のテキスト converted の offset にある Form:: の参照が、変換前のテキスト
+// original のどこにあったかを返す。書き換えた領域の中では、そのまま写された呼び出しを
+// 後に続く記述が最も長く一致する領域内の Form:: から探す。
+func (m sourceMap) originalOffset(original, converted string, offset int) int {
+	for _, s := range m {
+		if offset < s.outStart || offset >= s.outEnd {
+			continue
+		}
+		if s.copied {
+			return s.origStart + offset - s.outStart
+		}
+		best, bestLen := s.origStart, -1
+		for _, ref := range findFacadeRefs(original, s.origStart, s.origEnd) {
+			if n := commonPrefixLen(original[ref.start:s.origEnd], converted[offset:s.outEnd]); n > bestLen {
+				best, bestLen = ref.start, n
+			}
+		}
+		return best
+	}
+	return min(offset, len(original))
+}
+
+// commonPrefixLen は a と b の先頭から一致するバイト数を返す。
+func commonPrefixLen(a, b string) int {
+	n := 0
+	for n < len(a) && n < len(b) && a[n] == b[n] {
+		n++
+	}
+	return n
+}
+
+// callProblem は変換しなかった Form:: 呼び出し1件分（offset は "Form::" の位置）。
+type callProblem struct {
+	offset  int
+	method  string
+	message string
+	skipped bool
+}
+
+// replaceFormCalls はテンプレートを Blade の領域に分割し、{{ }} / {!! !!} のエコー内の
+// Form::メソッド(...) を対応するハンドラの出力に置き換える。エコー全体が呼び出し1つなら
+// そのまま置き換え、三項演算子や文字列連結に含まれる場合は式を Blade に書き換える（convertEchoRegion）。
+// 括弧が閉じていない・安全に書き換えられない等の呼び出しは変更せずに警告を返す。
+// コメント・エスケープされたエコー・@verbatim・<script> 等の中の Form:: は変換せず、理由とともに報告する。
+// 変換後のテキストの位置から変換前の位置を求める対応表も返す。
+func replaceFormCalls(text string, accept func(method string) bool) (string, []conversionWarning, sourceMap) {
+	var out strings.Builder
+	var problems []callProblem
+	var sources sourceMap
+	ctx := &formContext{}
+
+	for _, region := range lexBlade(text) {
+		if reason := region.skipReason(); reason != "" {
+			for _, ref := range findFacadeRefs(text, region.start, region.end) {
+				method := leadingIdentifier(text[ref.end:region.end])
+				if accept(strings.ToLower(method)) {
+					problems = append(problems, callProblem{ref.start, method, reason, true})
+				}
+			}
+			sources = append(sources, sourceSegment{region.start, region.end, out.Len(), out.Len() + region.end - region.start, true})
+			out.WriteString(text[region.start:region.end])
+			continue
+		}
+		html, converted, regionProblems := convertEchoRegion(ctx, text, region, accept)
+		problems = append(problems, regionProblems...)
+		if !converted {
+			html = text[region.start:region.end]
+		}
+		sources = append(sources, sourceSegment{region.start, region.end, out.Len(), out.Len() + len(html), !converted})
+		out.WriteString(html)
+	}
+
+	converted := out.String()
+	var warnings []conversionWarning
+	for _, p := range problems {
+		line, column := offsetPosition(text, p.offset)
+		warnings = append(warnings, conversionWarning{Line: line, Column: column, Method: p.method, Message: p.message, Skipped: p.skipped})
+	}
+	return converted, warnings, sources
+}
+
+// convertEchoRegion は1つの領域を変換する。Form:: を含まない領域や対象外のメソッドは converted=false。
+// 変換できなかった呼び出しは problems に返す（offset は text 上の位置）。
+func convertEchoRegion(ctx *formContext, text string, region bladeRegion, accept func(method string) bool) (string, bool, []callProblem) {
+	call, found := locateFormCall(text, region)
+	if found {
+		method := strings.ToLower(call.method)
+		handler := formHandlers[method]
+		switch {
+		case handler == nil || !accept(method):
+			return "", false, nil
+		case !call.closeFound:
+			return "", false, []callProblem{{offset: call.facade, method: call.method, message: "閉じ括弧が見つからないため変換できません"}}
+		case call.echoClosed:
+			html, ok := callHandler(ctx, method, handler, strings.TrimSpace(text[call.argsStart:call.argsEnd]))
+			if !ok {
+				return "", false, []callProblem{{offset: call.facade, method: call.method, message: "引数の形式に対応していないため変換できません"}}
+			}
+			return html, true, nil
+		}
+	}
+
+	// 三項演算子・文字列連結などの中の Form:: 呼び出し
+	bodyStart, bodyEnd, isEcho := region.echoBody(text)
+	refs := findFacadeRefs(text, region.start, region.end)
+	if !isEcho || len(refs) == 0 {
+		return "", false, nil
+	}
+	opener, closer := region.echoDelimiters()
+	html, exprProblems, err := rewriteEchoExpression(ctx, text[bodyStart:bodyEnd], opener, closer, accept)
+	if err != nil {
+		message := "式を解析できないため変換できません"
+		if found {
+			message = "呼び出しの直後でエコーが閉じていないため変換できません"
+		}
+		var problems []callProblem
+		for _, ref := range refs {
+			method := leadingIdentifier(text[ref.end:region.end])
+			if formHandlers[strings.ToLower(method)] != nil && accept(strings.ToLower(method)) {
+				problems = append(problems, callProblem{offset: ref.start, method: method, message: message})
+			}
+		}
+		return "", false, problems
+	}
+	for i := range exprProblems {
+		exprProblems[i].offset += bodyStart
+	}
+	return html, html != "" && len(exprProblems) == 0, exprProblems
+}
+
+// locateFormCall はエコー領域の式が Form:: 呼び出しで始まる場合に、その範囲を調べる。
+func locateFormCall(text string, region bladeRegion) (formCall, bool) {
+	facade, bodyEnd, ok := region.echoBody(text)
+	if !ok {
+		return formCall{}, false
+	}
+	for facade < bodyEnd && isBladeSpace(text[facade]) {
+		facade++
+	}
+	refs := findFacadeRefs(text, facade, bodyEnd)
+	if len(refs) == 0 || refs[0].start != facade {
+		return formCall{}, false
+	}
+	call := formCall{facade: facade, method: leadingIdentifier(text[refs[0].end:bodyEnd])}
+
+	// メソッド名の後の "("
+	open := refs[0].end + len(call.method)
+	for open < bodyEnd && isBladeSpace(text[open]) {
+		open++
+	}
+	if call.method == "" || open >= bodyEnd || text[open] != '(' {
+		return formCall{}, false
+	}
+	call.argsStart = open + 1
+	end, ok := matchingParen(text[:bodyEnd], open)
+	if !ok {
+		return call, true
+	}
+	call.argsEnd, call.closeFound = end, true
+	call.echoClosed = strings.TrimSpace(text[end+1:bodyEnd]) == ""
+	return call, true
+}
+
+// matchingParen は open の位置の "(" に対応する ")" の位置を返す。
+// 文字列リテラル内の括弧は無視し、括弧の種類が食い違った場合や
+// テキストの終わりに達した場合は見つからなかったものとする。
+func matchingParen(text string, open int) (int, bool) {
+	var stack []byte
+	var quote byte
+	for i := open; i < len(text); i++ {
+		c := text[i]
+		if quote != 0 {
+			if c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+			continue
+		}
+		switch c {
+		case '\'', '"':
+			quote = c
+		case '(':
+			stack = append(stack, ')')
+		case '[':
+			stack = append(stack, ']')
+		case '{':
+			stack = append(stack, '}')
+		case ')', ']', '}':
+			if stack[len(stack)-1] != c {
+				return 0, false
+			}
+			stack = stack[:len(stack)-1]
+			if len(stack) == 0 {
+				return i, true
+			}
+		}
+	}
+	return 0, false
+}
+
+// isBladeSpace は Blade のエコー内で読み飛ばす空白文字かを返す。
+func isBladeSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\r' || c == '\n'
+}
+
+// offsetPosition はバイト位置を1始まりの行・桁（桁は文字単位）に変換する。
+func offsetPosition(text string, offset int) (int, int) {
+	prefix := text[:offset]
+	line := strings.Count(prefix, "\n") + 1
+	lineStart := strings.LastIndex(prefix, "\n") + 1
+	return line, utf8.RuneCountInString(prefix[lineStart:]) + 1
+}
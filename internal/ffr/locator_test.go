@@ -0,0 +1,218 @@
+package ffr
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestConvertTemplateLocatesCalls(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+		warnings []conversionWarning
+	}{
+		{
+			name:     "Closing sequence inside a string argument",
+			input:    `{{ Form::text('a', ')}}') }}`,
+			expected: `<input type="text" name="a" value="{{ ')}}' }}">`,
+		},
+		{
+			name:     "Echo continues after the call",
+			input:    "{{ Form::text('a') $suffix }}\n<p>keep</p>\n{{ Form::email('b') }}",
+			expected: "{{ Form::text('a') $suffix }}\n<p>keep</p>\n<input type=\"email\" name=\"b\" value=\"\">",
+			warnings: []conversionWarning{
+				{Line: 1, Column: 4, Method: "text", Message: "呼び出しの直後でエコーが閉じていないため変換できません"},
+			},
+		},
+		{
+			name:     "Unbalanced call does not consume the rest of the file",
+			input:    "{{ Form::text('a' }}\n<div>\n{{ Form::text('b') }}\n</div>",
+			expected: "{{ Form::text('a' }}\n<div>\n<input type=\"text\" name=\"b\" value=\"\">\n</div>",
+			warnings: []conversionWarning{
+				{Line: 1, Column: 4, Method: "text", Message: "閉じ括弧が見つからないため変換できません"},
+			},
+		},
+		{
+			name:     "Unterminated string",
+			input:    "<p>{!! Form::label('a) !!}</p>",
+			expected: "<p>{!! Form::label('a) !!}</p>",
+			warnings: []conversionWarning{
+				{Line: 1, Column: 8, Method: "label", Message: "閉じ括弧が見つからないため変換できません"},
+			},
+		},
+		{
+			name:     "Arguments spanning lines",
+			input:    "{!! Form::text(\n    'name',\n    null,\n    ['class' => 'form-control']\n) !!}",
+			expected: `<input type="text" name="name" value="" class="form-control">`,
+		},
+		{
+			name:     "Method names are case-insensitive",
+			input:    `{{ Form::Text('a') }}`,
+			expected: `<input type="text" name="a" value="">`,
+		},
+		{
+			name:     "Handler rejects the arguments",
+			input:    "{!! Form::open(['method' => 'POST']) !!}\n{{ Form::select('x') }}",
+			expected: "<form action=\"\" method=\"POST\">\n{{ csrf_field() }}\n{{ Form::select('x') }}",
+			warnings: []conversionWarning{
+				{Line: 2, Column: 4, Method: "select", Message: "引数の形式に対応していないため変換できません"},
+			},
+		},
+		{
+			name:     "Close with arguments is left untouched",
+			input:    `{!! Form::close('x') !!}`,
+			expected: `{!! Form::close('x') !!}`,
+			warnings: []conversionWarning{
+				{Line: 1, Column: 5, Method: "close", Message: "引数の形式に対応していないため変換できません"},
+			},
+		},
+		{
+			name:     "Unknown methods are ignored",
+			input:    "{{ Form::macro('x') }}",
+			expected: "{{ Form::macro('x') }}",
+		},
+		{
+			name:     "Adjacent calls on one line",
+			input:    `{{ Form::label('a') }}{{ Form::text('a') }}`,
+			expected: `<label for="a">{!! 'a' !!}</label><input type="text" name="a" value="">`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, warnings := convertTemplate(tt.input)
+			if result != tt.expected {
+				t.Errorf("convertTemplate() =\n%s\nwant:\n%s", result, tt.expected)
+			}
+			if !reflect.DeepEqual(warnings, tt.warnings) {
+				t.Errorf("warnings = %+v, want %+v", warnings, tt.warnings)
+			}
+		})
+	}
+}
+
+func TestMatchingParen(t *testing.T) {
+	tests := []struct {
+		text     string
+		expected int
+		found    bool
+	}{
+		{`(a, b)`, 5, true},
+		{`('a)', ['x' => f(1)]) tail`, 20, true},
+		{`("a\")", 1)`, 10, true},
+		{`(['a' => 1)]`, 0, false},
+		{`('a', 'b'`, 0, false},
+	}
+	for _, tt := range tests {
+		end, found := matchingParen(tt.text, 0)
+		if end != tt.expected || found != tt.found {
+			t.Errorf("matchingParen(%q) = %d, %v, want %d, %v", tt.text, end, found, tt.expected, tt.found)
+		}
+	}
+}
+
+func TestReportUsesConversionWarnings(t *testing.T) {
+	original := "{{ Form::text('a') $suffix }}\n"
+	converted, warnings := convertTemplate(original)
+	report := buildFileReport(&FileResult{Path: "a.blade.php", Original: original, Converted: converted, Warnings: warnings})
+	expected := []reportMessage{{Line: 1, Column: 4, Method: "text", Message: "呼び出しの直後でエコーが閉じていないため変換できません"}}
+	if !reflect.DeepEqual(report.Warnings, expected) {
+		t.Errorf("Warnings = %v, want %v", report.Warnings, expected)
+	}
+	if len(report.Remaining) != 1 {
+		t.Errorf("Remaining = %+v, want 1 occurrence", report.Remaining)
+	}
+}
+
+func TestReportUsesOriginalPositions(t *testing.T) {
+	// 前の select が複数行に展開されても、レポートの位置は変換前のファイルを指す
+	original := "{{ Form::select('size', ['L' => 'Large', 'S' => 'Small']) }}\n" +
+		"{{ Form::text('a') $suffix }}\n" +
+		"<p>{{ Form::customMacro('x') }}</p>\n" +
+		"{{-- Form::text('b') --}}\n"
+	converted, warnings, sources := convertTemplateMapped(original)
+	report := buildFileReport(&FileResult{Path: "a.blade.php", Original: original, Converted: converted, Warnings: warnings, Sources: sources})
+
+	expectedRemaining := []remainingFacade{
+		{Line: 2, Column: 4, Method: "text", Text: "{{ Form::text('a') $suffix }}"},
+		{Line: 3, Column: 7, Method: "customMacro", Text: "<p>{{ Form::customMacro('x') }}</p>"},
+	}
+	if !reflect.DeepEqual(report.Remaining, expectedRemaining) {
+		t.Errorf("Remaining = %+v, want %+v", report.Remaining, expectedRemaining)
+	}
+	if len(report.Warnings) != 2 || report.Warnings[0].Line != 2 || report.Warnings[1].Line != 3 || report.Warnings[1].Column != 7 {
+		t.Errorf("Warnings = %+v, want lines 2 and 3", report.Warnings)
+	}
+	expectedSkipped := []reportMessage{{Line: 4, Column: 6, Method: "text", Message: "Blade コメント内のため変換しません"}}
+	if !reflect.DeepEqual(report.Skipped, expectedSkipped) {
+		t.Errorf("Skipped = %+v, want %+v", report.Skipped, expectedSkipped)
+	}
+}
+
+func TestPositionalSource(t *testing.T) {
+	tests := []struct {
+		method   string
+		args     string
+		expected string
+		ok       bool
+	}{
+		{"text", `'email', null`, `'email', null`, true},
+		{"text", `name: 'email', options: ['class' => 'x']`, `'email', null, ['class' => 'x']`, true},
+		{"checkbox", `'agree', checked: $on`, `'agree', 1, $on`, true},
+		{"select", `'s', $list, selectAttributes: ['id' => 's']`, `'s', $list, null, ['id' => 's']`, true},
+		{"input", `type: 'email', name: 'e'`, `'email', 'e'`, true},
+		{"text", `value: 'x'`, "", false},
+		{"text", `'a', name: 'b'`, "", false},
+		{"text", `'a', placeholder: 'b'`, "", false},
+		{"macro", `name: 'a'`, "", false},
+	}
+	for _, tt := range tests {
+		got, ok := positionalSource(tt.method, tt.args)
+		if got != tt.expected || ok != tt.ok {
+			t.Errorf("positionalSource(%q, %q) = %q, %v, want %q, %v", tt.method, tt.args, got, ok, tt.expected, tt.ok)
+		}
+	}
+}
+
+func TestConvertTemplateLongArraysAndNamedArguments(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:  "Form::open with array()",
+			input: `{!! Form::open(array('route' => array('users.update', $user->id), 'class' => 'f')) !!}`,
+			expected: `<form action="{{ route('users.update', $user->id) }}" method="POST" class="f">
+{{ csrf_field() }}`,
+		},
+		{
+			name:     "Attributes given with array()",
+			input:    `{{ Form::text('email', null, array('class' => 'form-control', 'placeholder' => 'Email')) }}`,
+			expected: `<input type="text" name="email" value="" placeholder="Email" class="form-control">`,
+		},
+		{
+			name:     "Button with array()",
+			input:    `{{ Form::button('Go', array('type' => 'submit', 'data-x' => '1')) }}`,
+			expected: `<button type="submit" data-x="1">{!! 'Go' !!}</button>`,
+		},
+		{
+			name:     "Named arguments",
+			input:    `{{ Form::text(name: 'email', options: ['class' => 'x']) }}`,
+			expected: `<input type="text" name="email" value="" class="x">`,
+		},
+		{
+			name:     "Named arguments fill skipped parameters with defaults",
+			input:    `{{ Form::checkbox('agree', checked: true) }}`,
+			expected: `<input type="checkbox" name="agree" value="1" checked>`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got, _ := convertTemplate(tt.input); got != tt.expected {
+				t.Errorf("convertTemplate() =\n%s\nwant:\n%s", got, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,138 @@
+// model_binding.go: Form::model で束縛したモデルと old() の入力値から、フィールドの値を補う処理。
+package ffr
+
+import (
+	"fmt"
+	"strings"
+)
+
+// formContext は1つのテンプレートの変換中に、呼び出しをまたいで引き継ぐ状態。
+// テンプレートごとに作るため、並列処理でもファイル間で共有しない。
+type formContext struct {
+	model *phpNode // Form::model で束縛されたモデルの式（対応する Form::close まで）
+}
+
+// boundParams は Form::model のモデルや old() の入力値で補う引数（メソッド名ごと）。
+// password・file・ボタン・ラベルは Collective と同じく補わない。
+var boundParams = map[string]string{
+	"text": "value", "email": "value", "url": "value", "tel": "value", "search": "value",
+	"date": "value", "time": "value", "datetime": "value", "range": "value", "color": "value",
+	"number": "value", "hidden": "value", "textarea": "value", "input": "value",
+	"select": "selected", "selectrange": "selected", "selectyear": "selected", "selectmonth": "selected",
+	"checkbox": "checked", "radio": "checked",
+}
+
+// skipValueTypes は Form::input で値を補わない type（Collective の skipValueTypes と同じ）。
+var skipValueTypes = []string{"file", "password", "checkbox", "radio"}
+
+// track は変換した呼び出しに応じて、束縛中のモデルを切り替える。
+func (c *formContext) track(method, args string) {
+	switch method {
+	case "model":
+		if nodes, ok := positionalArgs(args); ok && len(nodes) > 0 {
+			c.model = nodes[0]
+		}
+	case "open", "close":
+		c.model = nil
+	}
+}
+
+// bindValue は値・選択状態・チェック状態の引数を、Form::model のモデルと old() の入力値で補う。
+//   - Form::model の内側で値が省略された（null の）場合: old('name', $model->name)
+//   - old_input が有効な場合: old('name', 値)（値がなければ old('name')）。チェック状態は
+//     old の入力があればその値から、なければ指定どおりに決める
+//
+// フィールド名が文字列リテラルでない場合は補わない。
+func (c *formContext) bindValue(method, args string) string {
+	param, bound := boundParams[method]
+	if !bound || c.model == nil && !settings.OldInput {
+		return args
+	}
+	nodes, ok := positionalArgs(args)
+	if !ok {
+		return args
+	}
+	params := formParams[method]
+	nameIndex, index := paramIndex(params, "name"), paramIndex(params, param)
+	if len(nodes) <= nameIndex {
+		return args
+	}
+	if inputType, ok := nodes[0].stringLiteral(); method == "input" && (!ok || containsString(skipValueTypes, inputType)) {
+		return args
+	}
+	name, ok := nodes[nameIndex].stringLiteral()
+	if !ok || name == "" || name == "_method" || name == "_token" {
+		return args
+	}
+	values := make([]string, max(len(nodes), index+1))
+	for i := range values {
+		if i < len(nodes) {
+			values[i] = nodes[i].Src
+		} else {
+			values[i] = params[i].defaultValue
+		}
+	}
+	current := ""
+	if index < len(nodes) && !nodes[index].isConst("null") {
+		current = values[index]
+	}
+	key := phpSingleQuote(oldInputKey(name))
+	compare := ""
+	if method == "radio" {
+		compare = " == " + values[paramIndex(params, "value")]
+	}
+	switch {
+	case current == "" && c.model != nil:
+		values[index] = c.modelValue(name) + compare
+	case !settings.OldInput:
+		return args
+	case current == "":
+		values[index] = fmt.Sprintf("old(%s)%s", key, compare)
+	case method == "checkbox" || method == "radio":
+		if node := nodes[index]; node.Kind == phpTernary || node.Kind == phpBinary && phpBinaryPrecedence[node.Value] < phpTernaryPrecedence {
+			current = "(" + current + ")"
+		}
+		values[index] = fmt.Sprintf("session()->hasOldInput() ? old(%s)%s : %s", key, compare, current)
+		if method == "checkbox" && strings.HasSuffix(name, "[]") {
+			// (array) のキャストより三項演算子の結合が弱いため括弧で囲む
+			values[index] = "(" + values[index] + ")"
+		}
+	default:
+		values[index] = fmt.Sprintf("old(%s, %s)", key, current)
+	}
+	return strings.Join(values, ", ")
+}
+
+// modelValue はフィールド name の old() とモデルの値の式を返す
+// （address[city] → old('address.city', data_get($user, 'address.city'))）。
+func (c *formContext) modelValue(name string) string {
+	key := oldInputKey(name)
+	if isPHPIdentifier(key) {
+		return fmt.Sprintf("old(%s, %s->%s)", phpSingleQuote(key), c.model.operandSrc(), key)
+	}
+	return fmt.Sprintf("old(%s, data_get(%s, %s))", phpSingleQuote(key), c.model.Src, phpSingleQuote(key))
+}
+
+// oldInputKey はフィールド名を old() のドット区切りのキーにする（Collective の transformKey と同じ）。
+// items[0][qty] → items.0.qty、roles[] → roles
+var oldInputKey = strings.NewReplacer(".", "_", "[]", "", "[", ".", "]", "").Replace
+
+// paramIndex は引数の並び params から name の位置を返す（なければ -1）。
+func paramIndex(params []formParam, name string) int {
+	for i, param := range params {
+		if param.name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// isPHPIdentifier は s がプロパティ名としてそのまま書ける識別子かを返す。
+func isPHPIdentifier(s string) bool {
+	return s != "" && isPHPNameStart(s[0]) && scanPHPName(s, 0) == len(s)
+}
+
+// phpSingleQuote は s を PHP のシングルクォート文字列リテラルにする。
+func phpSingleQuote(s string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(s) + "'"
+}
@@ -0,0 +1,139 @@
+package ffr
+
+import "testing"
+
+func TestConvertTemplateFormModel(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:     "Form::model becomes a form tag",
+			input:    `{!! Form::model($user, ['route' => ['users.update', $user->id], 'method' => 'POST', 'class' => 'f']) !!}`,
+			expected: "<form action=\"{{ route('users.update', $user->id) }}\" method=\"POST\" class=\"f\">\n{{ csrf_field() }}",
+		},
+		{
+			name:     "Fields without values read the model",
+			input:    "{!! Form::model($user) !!}\n{{ Form::text('name') }}\n{{ Form::email('email', null, ['class' => 'c']) }}\n{{ Form::textarea('bio') }}\n{!! Form::close() !!}",
+			expected: "<form action=\"\" method=\"POST\">\n{{ csrf_field() }}\n<input type=\"text\" name=\"name\" value=\"{{ old('name', $user->name) }}\">\n<input type=\"email\" name=\"email\" value=\"{{ old('email', $user->email) }}\" class=\"c\">\n<textarea name=\"bio\">{{ old('bio', $user->bio) }}</textarea>\n</form>",
+		},
+		{
+			name:     "Explicit values are kept",
+			input:    "{!! Form::model($user) !!}\n{{ Form::text('name', 'fixed') }}\n{{ Form::hidden('id', $user->id) }}",
+			expected: "<form action=\"\" method=\"POST\">\n{{ csrf_field() }}\n<input type=\"text\" name=\"name\" value=\"{{ 'fixed' }}\">\n<input type=\"hidden\" name=\"id\" value=\"{{ $user->id }}\">",
+		},
+		{
+			name:     "Nested names use data_get",
+			input:    "{!! Form::model($user) !!}\n{{ Form::text('address[city]') }}\n{{ Form::number('items[0][qty]') }}",
+			expected: "<form action=\"\" method=\"POST\">\n{{ csrf_field() }}\n<input type=\"text\" name=\"address[city]\" value=\"{{ old('address.city', data_get($user, 'address.city')) }}\">\n<input type=\"number\" name=\"items[0][qty]\" value=\"{{ old('items.0.qty', data_get($user, 'items.0.qty')) }}\">",
+		},
+		{
+			name:     "Select, checkbox and radio",
+			input:    "{!! Form::model($post) !!}\n{{ Form::select('status', $statuses) }}\n{{ Form::checkbox('published') }}\n{{ Form::radio('color', 'red') }}",
+			expected: "<form action=\"\" method=\"POST\">\n{{ csrf_field() }}\n<select name=\"status\">\n@foreach($statuses as $key => $value)\n<option value=\"{{ $key }}\" @if($key == old('status', $post->status)) selected @endif>{{ $value }}</option>\n@endforeach\n</select>\n<input type=\"checkbox\" name=\"published\" value=\"1\" @if(old('published', $post->published)) checked @endif>\n<input type=\"radio\" name=\"color\" value=\"{{ 'red' }}\" @if(old('color', $post->color) == 'red') checked @endif>",
+		},
+		{
+			name:     "Multiple selects compare with the array of values",
+			input:    "{!! Form::model($post) !!}\n{{ Form::select('tags[]', $tags) }}\n{{ Form::select('roles', $roles, null, ['multiple']) }}",
+			expected: "<form action=\"\" method=\"POST\">\n{{ csrf_field() }}\n<select name=\"tags[]\">\n@foreach($tags as $key => $value)\n<option value=\"{{ $key }}\" @if(in_array($key, (array)old('tags', $post->tags))) selected @endif>{{ $value }}</option>\n@endforeach\n</select>\n<select name=\"roles\" multiple>\n@foreach($roles as $key => $value)\n<option value=\"{{ $key }}\" @if(in_array($key, (array)old('roles', $post->roles))) selected @endif>{{ $value }}</option>\n@endforeach\n</select>",
+		},
+		{
+			name:     "Range and month selects",
+			input:    "{!! Form::model($user) !!}\n{{ Form::selectRange('age', 1, 2) }}\n{{ Form::selectMonth('birth_month') }}",
+			expected: "<form action=\"\" method=\"POST\">\n{{ csrf_field() }}\n<select name=\"age\">\n<option value=\"1\" @if(1 == old('age', $user->age)) selected @endif>1</option>\n<option value=\"2\" @if(2 == old('age', $user->age)) selected @endif>2</option>\n</select>\n<select name=\"birth_month\">\n@foreach(range(1, 12) as $__v)\n<option value=\"{{ $__v }}\" @if($__v == old('birth_month', $user->birth_month)) selected @endif>{{ \\Illuminate\\Support\\Carbon::create(null, $__v, 1)->translatedFormat('F') }}</option>\n@endforeach\n</select>",
+		},
+		{
+			name:     "Password and file are not filled",
+			input:    "{!! Form::model($user) !!}\n{{ Form::password('password') }}\n{{ Form::file('avatar') }}",
+			expected: "<form action=\"\" method=\"POST\">\n{{ csrf_field() }}\n<input type=\"password\" name=\"password\" value=\"\">\n<input type=\"file\" name=\"avatar\">",
+		},
+		{
+			name:     "Model is released by Form::close",
+			input:    "{!! Form::model($user) !!}\n{!! Form::close() !!}\n{{ Form::text('name') }}",
+			expected: "<form action=\"\" method=\"POST\">\n{{ csrf_field() }}\n</form>\n<input type=\"text\" name=\"name\" value=\"\">",
+		},
+		{
+			name:     "Model expression that needs parentheses",
+			input:    "{!! Form::model($user ?? new User) !!}\n{{ Form::text('name') }}",
+			expected: "<form action=\"\" method=\"POST\">\n{{ csrf_field() }}\n<input type=\"text\" name=\"name\" value=\"{{ old('name', ($user ?? new User)->name) }}\">",
+		},
+		{
+			name:     "Dynamic field names are left without a value",
+			input:    "{!! Form::model($user) !!}\n{{ Form::text('tag_' . $i) }}",
+			expected: "<form action=\"\" method=\"POST\">\n{{ csrf_field() }}\n<input type=\"text\" name=\"tag_{{ $i }}\" value=\"\">",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got, _ := convertTemplate(tt.input); got != tt.expected {
+				t.Errorf("convertTemplate() =\n%s\nwant:\n%s", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestOldInputKey(t *testing.T) {
+	tests := map[string]string{
+		"name":          "name",
+		"address[city]": "address.city",
+		"items[0][qty]": "items.0.qty",
+		"roles[]":       "roles",
+		"user.name":     "user_name",
+	}
+	for name, expected := range tests {
+		if got := oldInputKey(name); got != expected {
+			t.Errorf("oldInputKey(%q) = %q, want %q", name, got, expected)
+		}
+	}
+}
+
+func TestConvertTemplateOldInput(t *testing.T) {
+	s := defaultSettings()
+	s.OldInput = true
+	useSettings(t, s)
+
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:     "Values",
+			input:    "{{ Form::text('name') }}\n{{ Form::email('email', $user->email) }}\n{{ Form::textarea('items[0][note]', 'memo') }}",
+			expected: "<input type=\"text\" name=\"name\" value=\"{{ old('name') }}\">\n<input type=\"email\" name=\"email\" value=\"{{ old('email', $user->email) }}\">\n<textarea name=\"items[0][note]\">{{ old('items.0.note', 'memo') }}</textarea>",
+		},
+		{
+			name:     "Selected state",
+			input:    `{{ Form::select('size', $sizes, 'M') }}`,
+			expected: "<select name=\"size\">\n@foreach($sizes as $key => $value)\n<option value=\"{{ $key }}\" @if($key == old('size', 'M')) selected @endif>{{ $value }}</option>\n@endforeach\n</select>",
+		},
+		{
+			name:     "Selected state of a multiple select",
+			input:    `{{ Form::select('sizes[]', $sizes, $chosen, ['multiple' => true]) }}`,
+			expected: "<select name=\"sizes[]\" multiple>\n@foreach($sizes as $key => $value)\n<option value=\"{{ $key }}\" @if(in_array($key, (array)old('sizes', $chosen))) selected @endif>{{ $value }}</option>\n@endforeach\n</select>",
+		},
+		{
+			name:     "Checked state",
+			input:    "{{ Form::checkbox('agree') }}\n{{ Form::checkbox('roles[]', 'admin', $isAdmin) }}\n{{ Form::radio('color', 'red', $a ?: $b) }}",
+			expected: "<input type=\"checkbox\" name=\"agree\" value=\"1\" @if(old('agree')) checked @endif>\n<input type=\"checkbox\" name=\"roles[]\" value=\"admin\" @if(in_array('admin', (array)(session()->hasOldInput() ? old('roles') : $isAdmin))) checked @endif>\n<input type=\"radio\" name=\"color\" value=\"{{ 'red' }}\" @if(session()->hasOldInput() ? old('color') == 'red' : ($a ?: $b)) checked @endif>",
+		},
+		{
+			name:     "Passwords, files and _method are excluded",
+			input:    "{{ Form::password('password') }}\n{{ Form::file('avatar') }}\n{{ Form::input('password', 'pin') }}\n{{ Form::hidden('_method', 'PUT') }}",
+			expected: "<input type=\"password\" name=\"password\" value=\"\">\n<input type=\"file\" name=\"avatar\">\n<input type=\"password\" name=\"pin\" value=\"\">\n<input type=\"hidden\" name=\"_method\" value=\"{{ 'PUT' }}\">",
+		},
+		{
+			name:     "Model values already use old()",
+			input:    "{!! Form::model($user) !!}\n{{ Form::text('name') }}\n{{ Form::text('nick', 'x') }}",
+			expected: "<form action=\"\" method=\"POST\">\n{{ csrf_field() }}\n<input type=\"text\" name=\"name\" value=\"{{ old('name', $user->name) }}\">\n<input type=\"text\" name=\"nick\" value=\"{{ old('nick', 'x') }}\">",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got, _ := convertTemplate(tt.input); got != tt.expected {
+				t.Errorf("convertTemplate() =\n%s\nwant:\n%s", got, tt.expected)
+			}
+		})
+	}
+}
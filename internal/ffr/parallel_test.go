@@ -0,0 +1,131 @@
+package ffr
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// setupParallelTest は並列処理の検証用に多数の Blade ファイルを作成し、走査順のパスを返す。
+func setupParallelTest(t *testing.T, count int) (string, []string) {
+	t.Helper()
+	root := t.TempDir()
+	var paths []string
+	for i := 0; i < count; i++ {
+		dir := filepath.Join(root, fmt.Sprintf("dir%d", i%4))
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatalf("Failed to create dir: %v", err)
+		}
+		path := filepath.Join(dir, fmt.Sprintf("form%03d.blade.php", i))
+		content := fmt.Sprintf("<div>\n{{ Form::text('field%d', $value) }}\n{!! Form::submit('Save') !!}\n</div>\n", i)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to create test file: %v", err)
+		}
+	}
+	err := walkTargetFiles(&ReplacementConfig{TargetPath: root}, func(path string) error {
+		paths = append(paths, path)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("walkTargetFiles() error: %v", err)
+	}
+	return root, paths
+}
+
+func TestParallelProcessingIsDeterministic(t *testing.T) {
+	useSettings(t, defaultSettings())
+	var expected []string
+
+	for _, jobs := range []int{1, 3, 16} {
+		t.Run(fmt.Sprintf("jobs=%d", jobs), func(t *testing.T) {
+			root, paths := setupParallelTest(t, 40)
+			config := newReplacementConfig()
+			config.TargetPath = root
+			config.Jobs = jobs
+			if err := processBladeFiles(config); err != nil {
+				t.Fatalf("processBladeFiles() error: %v", err)
+			}
+
+			if config.FileCount != len(paths) {
+				t.Errorf("FileCount = %d, want %d", config.FileCount, len(paths))
+			}
+			if !reflect.DeepEqual(config.ProcessedFiles, paths) {
+				t.Errorf("ProcessedFiles are not in path order:\n%v", config.ProcessedFiles)
+			}
+			for i, result := range config.Results {
+				if result.Path != paths[i] {
+					t.Errorf("Results[%d].Path = %s, want %s", i, result.Path, paths[i])
+				}
+			}
+
+			var converted []string
+			for _, path := range paths {
+				content, _ := os.ReadFile(path)
+				converted = append(converted, string(content))
+			}
+			if expected == nil {
+				expected = converted
+			} else if !reflect.DeepEqual(converted, expected) {
+				t.Error("conversion result differs from the sequential run")
+			}
+		})
+	}
+}
+
+func TestParallelProcessingStopsAtFirstErrorInPathOrder(t *testing.T) {
+	useSettings(t, defaultSettings())
+	root, paths := setupParallelTest(t, 12)
+	// dir1 の先頭に読み込めないファイルを置く
+	broken := filepath.Join(root, "dir1", "form000a.blade.php")
+	if err := os.Symlink(filepath.Join(root, "missing"), broken); err != nil {
+		t.Skipf("symlinks are not supported: %v", err)
+	}
+
+	config := newReplacementConfig()
+	config.TargetPath = root
+	config.Jobs = 4
+	if err := processBladeFiles(config); err == nil {
+		t.Fatal("processBladeFiles() should fail")
+	}
+
+	// dir0 のファイルだけが処理され、エラー以降のファイルは書き換えられない
+	for _, path := range paths {
+		hasFacade, _ := containsFormFacade(path)
+		expectConverted := filepath.Base(filepath.Dir(path)) == "dir0"
+		if hasFacade == expectConverted {
+			t.Errorf("%s: converted = %v, want %v", path, !hasFacade, expectConverted)
+		}
+	}
+	if config.FileCount != 3 {
+		t.Errorf("FileCount = %d, want 3", config.FileCount)
+	}
+}
+
+func TestParseArgsJobs(t *testing.T) {
+	tests := []struct {
+		args     []string
+		expected int
+		wantErr  bool
+	}{
+		{[]string{"views"}, 0, false},
+		{[]string{"-j", "4", "views"}, 4, false},
+		{[]string{"-j8", "views"}, 8, false},
+		{[]string{"--jobs=2", "views"}, 2, false},
+		{[]string{"--jobs", "0", "views"}, 0, true},
+		{[]string{"-j", "many", "views"}, 0, true},
+	}
+
+	for _, tt := range tests {
+		config := newReplacementConfig()
+		_, err := parseArgs(tt.args, config)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseArgs(%v) error = %v, wantErr %v", tt.args, err, tt.wantErr)
+			continue
+		}
+		if !tt.wantErr && config.Jobs != tt.expected {
+			t.Errorf("parseArgs(%v) Jobs = %d, want %d", tt.args, config.Jobs, tt.expected)
+		}
+	}
+}
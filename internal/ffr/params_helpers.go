@@ -1,74 +1,38 @@
 package ffr
 
-import (
-	"strings"
-)
+import "strings"
 
-// パラメータ抽出（バランス括弧対応）
-func extractParamsBalanced(paramsStr string) []string {
-	var params []string
-	var current strings.Builder
-	depth := 0
-	inQuotes := false
-	var quoteChar rune
-	escape := false
-
-	for _, ch := range paramsStr {
-		if escape {
-			current.WriteRune(ch)
-			escape = false
+// JS文字列リテラル/イベント属性の一部変換
+// convertJavaScriptStringLiterals は JavaScript の "..." の文字列リテラルを '...' にする。
+// エスケープや ' を含むリテラル、'...' の内側はそのまま残す。
+func convertJavaScriptStringLiterals(jsCode string) string {
+	var b strings.Builder
+	for i := 0; i < len(jsCode); i++ {
+		c := jsCode[i]
+		if c != '"' && c != '\'' {
+			b.WriteByte(c)
 			continue
 		}
-		if ch == '\\' && inQuotes {
-			current.WriteRune(ch)
-			escape = true
-			continue
+		end := i + 1
+		for end < len(jsCode) && jsCode[end] != c {
+			if jsCode[end] == '\\' {
+				end++
+			}
+			end++
 		}
-		if (ch == '\'' || ch == '"') && !inQuotes {
-			inQuotes = true
-			quoteChar = ch
-		} else if inQuotes && ch == quoteChar {
-			inQuotes = false
+		if end >= len(jsCode) {
+			b.WriteString(jsCode[i:])
+			break
 		}
-		if !inQuotes {
-			switch ch {
-			case '(', '[', '{':
-				depth++
-			case ')', ']', '}':
-				depth--
-			case ',':
-				if depth == 0 {
-					params = append(params, strings.TrimSpace(current.String()))
-					current.Reset()
-					continue
-				}
-			}
+		content := jsCode[i+1 : end]
+		if c == '"' && !strings.ContainsAny(content, `'\`) {
+			b.WriteString("'" + content + "'")
+		} else {
+			b.WriteString(jsCode[i : end+1])
 		}
-		current.WriteRune(ch)
-	}
-	if strings.TrimSpace(current.String()) != "" {
-		params = append(params, strings.TrimSpace(current.String()))
+		i = end
 	}
-	return params
-}
-
-// 高度な抽出（フォールバック用）
-func extractParamsAdvanced(paramsStr string) []string {
-	return extractParamsBalanced(paramsStr)
-}
-
-func extractParams(paramsStr string) []string {
-	return extractParamsBalanced(paramsStr)
-}
-
-// JS文字列リテラル/イベント属性の一部変換
-func convertJavaScriptStringLiterals(jsCode string) string {
-	result := jsCode
-	re := regexCache.GetRegex(`\"([^\"\\]+)\"`)
-	for re.MatchString(result) {
-		result = re.ReplaceAllString(result, "'$1'")
-	}
-	return result
+	return b.String()
 }
 
 func convertEventHandlerQuotesInHTML(html string) string {
@@ -0,0 +1,233 @@
+// php_interpolation.go: ダブルクォート文字列・ヒアドキュメントのエスケープと変数展開の解析。
+package ffr
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// phpStringPart は変数展開を含む文字列の一部分（Expr が空でなければ展開される式）。
+type phpStringPart struct {
+	Text string // エスケープを展開した文字列
+	Expr string // 展開される式の PHP ソース（"{$row->id}" なら $row->id、"$a[key]" なら $a['key']）
+}
+
+// phpEscapes はダブルクォート文字列の1文字のエスケープシーケンス
+var phpEscapes = map[byte]string{
+	'n': "\n", 't': "\t", 'r': "\r", 'v': "\v", 'e': "\x1b", 'f': "\f",
+	'\\': `\`, '$': "$", '"': `"`,
+}
+
+// unescapeDoubleQuoted はダブルクォート文字列（quote='"'）・ヒアドキュメント（quote=0）の本文の
+// エスケープを展開する。変数展開を含む場合は、文字列と式に分けた parts も返す。
+// 戻り値の文字列では、変数展開の部分は記述のまま残す。
+func unescapeDoubleQuoted(s string, quote byte) (string, []phpStringPart) {
+	var value, text strings.Builder
+	var parts []phpStringPart
+	flush := func() {
+		if text.Len() > 0 {
+			parts = append(parts, phpStringPart{Text: text.String()})
+			text.Reset()
+		}
+	}
+	for i := 0; i < len(s); {
+		c := s[i]
+		if c == '\\' && i+1 < len(s) {
+			if decoded, n := phpEscape(s[i+1:], quote); n > 0 {
+				value.WriteString(decoded)
+				text.WriteString(decoded)
+				i += 1 + n
+				continue
+			}
+		}
+		if expr, n := interpolationAt(s[i:]); n > 0 {
+			flush()
+			parts = append(parts, phpStringPart{Expr: expr})
+			value.WriteString(s[i : i+n])
+			i += n
+			continue
+		}
+		value.WriteByte(c)
+		text.WriteByte(c)
+		i++
+	}
+	hasExpr := false
+	for _, part := range parts {
+		hasExpr = hasExpr || part.Expr != ""
+	}
+	if !hasExpr {
+		return value.String(), nil
+	}
+	flush()
+	return value.String(), parts
+}
+
+// phpEscape は "\" の直後の s から始まるエスケープシーケンスを展開し、消費したバイト数を返す。
+// エスケープでなければ 0 を返す（"\" はそのまま残る）。
+func phpEscape(s string, quote byte) (string, int) {
+	c := s[0]
+	switch {
+	case c == '"' && quote != '"':
+		// ヒアドキュメントの \" はエスケープではない
+		return "", 0
+	case phpEscapes[c] != "":
+		return phpEscapes[c], 1
+	case c >= '0' && c <= '7':
+		n := 1
+		for n < 3 && n < len(s) && s[n] >= '0' && s[n] <= '7' {
+			n++
+		}
+		v, _ := strconv.ParseUint(s[:n], 8, 32)
+		return string([]byte{byte(v)}), n
+	case c == 'x' && len(s) > 1 && isHexDigit(s[1]):
+		n := 2
+		if n < len(s) && isHexDigit(s[n]) {
+			n++
+		}
+		v, _ := strconv.ParseUint(s[1:n], 16, 8)
+		return string([]byte{byte(v)}), n
+	case c == 'u' && strings.HasPrefix(s[1:], "{"):
+		end := strings.IndexByte(s, '}')
+		if end < 0 {
+			return "", 0
+		}
+		v, err := strconv.ParseUint(s[2:end], 16, 32)
+		if err != nil || !utf8.ValidRune(rune(v)) {
+			return "", 0
+		}
+		return string(rune(v)), end + 1
+	}
+	return "", 0
+}
+
+// isHexDigit は16進数の数字かを返す。
+func isHexDigit(c byte) bool {
+	return c >= '0' && c <= '9' || c >= 'a' && c <= 'f' || c >= 'A' && c <= 'F'
+}
+
+// interpolationAt は s の先頭が変数展開（$var / $var[key] / $var->prop / {$expr} / ${name}）なら、
+// 展開される式の PHP ソースと消費したバイト数を返す。
+func interpolationAt(s string) (string, int) {
+	switch {
+	case strings.HasPrefix(s, "{$"):
+		end := closingBrace(s)
+		if end < 0 {
+			return "", 0
+		}
+		return s[1:end], end + 1
+	case strings.HasPrefix(s, "${"):
+		end := closingBrace(s[1:])
+		if end < 0 {
+			return "", 0
+		}
+		inner := s[2 : end+1]
+		if inner != "" && isPHPNameStart(inner[0]) {
+			return "$" + inner, end + 2
+		}
+		return "${" + inner + "}", end + 2
+	case len(s) > 1 && s[0] == '$' && isPHPNameStart(s[1]):
+		n := scanPHPName(s, 1)
+		expr := s[:n]
+		switch {
+		case strings.HasPrefix(s[n:], "["):
+			// 添字は1段階だけ（クォートなしのキーは文字列）
+			end := strings.IndexByte(s[n:], ']')
+			if end < 0 {
+				return expr, n
+			}
+			key := s[n+1 : n+end]
+			if key == "" {
+				return expr, n
+			}
+			if scanPHPName(key, 0) == len(key) && isPHPNameStart(key[0]) {
+				key = "'" + key + "'"
+			}
+			return fmt.Sprintf("%s[%s]", expr, key), n + end + 1
+		case strings.HasPrefix(s[n:], "->") && n+2 < len(s) && isPHPNameStart(s[n+2]):
+			end := scanPHPName(s, n+2)
+			return s[:end], end
+		case strings.HasPrefix(s[n:], "?->") && n+3 < len(s) && isPHPNameStart(s[n+3]):
+			end := scanPHPName(s, n+3)
+			return s[:end], end
+		}
+		return expr, n
+	}
+	return "", 0
+}
+
+// closingBrace は s の先頭の "{" に対応する "}" の位置を返す（文字列リテラル内は無視する）。
+func closingBrace(s string) int {
+	depth := 0
+	var quote byte
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if quote != 0 {
+			if c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+			continue
+		}
+		switch c {
+		case '\'', '"':
+			quote = c
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// bladeText は文字列リテラルを Blade のテキストにする（変数展開の部分は {{ 式 }}）。
+// リテラルの部分は escapeBladeText でエスケープする。文字列リテラルでなければ ok=false。
+func (n *phpNode) bladeText() (string, bool) {
+	if n == nil || n.Kind != phpString {
+		return "", false
+	}
+	if !n.Interp {
+		return escapeBladeText(n.Value), true
+	}
+	var b strings.Builder
+	for _, part := range n.Parts {
+		if part.Expr != "" {
+			fmt.Fprintf(&b, "{{ %s }}", part.Expr)
+		} else {
+			b.WriteString(escapeBladeText(part.Text))
+		}
+	}
+	return b.String(), true
+}
+
+// bladeTextEscaper は静的なテキストを HTML の属性値・内容として書けるようにする
+// （Collective と同じく & " < > をエスケープし、{{ / {!! は Blade のエコーにならないよう @ を付ける）。
+var bladeTextEscaper = strings.NewReplacer(
+	"&", "&amp;", `"`, "&quot;", "<", "&lt;", ">", "&gt;",
+	"{{", "@{{", "{!!", "@{!!",
+)
+
+// escapeBladeText は PHP の文字列リテラルの値を、そのまま表示される Blade のテキストにする。
+// ディレクティブとして解釈される @（@if 等）は @@ にする。
+func escapeBladeText(text string) string {
+	text = bladeTextEscaper.Replace(text)
+	var b strings.Builder
+	for i := 0; i < len(text); i++ {
+		if text[i] == '@' && (i == 0 || !isBladeWordByte(text[i-1])) && i+1 < len(text) && isBladeWordByte(text[i+1]) {
+			b.WriteByte('@')
+		}
+		b.WriteByte(text[i])
+	}
+	return b.String()
+}
+
+// isBladeWordByte は c が Blade のディレクティブ名に使われる文字（\w）かを返す。
+func isBladeWordByte(c byte) bool {
+	return c == '_' || '0' <= c && c <= '9' || 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z'
+}
@@ -0,0 +1,95 @@
+package ffr
+
+import (
+	"testing"
+)
+
+func TestBladeTextOfDoubleQuotedStrings(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`"items[{$row->id}][qty]"`, `items[{{ $row->id }}][qty]`},
+		{`"option_$i"`, `option_{{ $i }}`},
+		{`"user-${id}"`, `user-{{ $id }}`},
+		{`"$user->name!"`, `{{ $user->name }}!`},
+		{`"$row[id]-$row[0]-$row[$k]"`, `{{ $row['id'] }}-{{ $row[0] }}-{{ $row[$k] }}`},
+		{`"{$data['a']['b']}"`, `{{ $data['a']['b'] }}`},
+		{`"\$notVar {\$x} \x41\101\u{3042}"`, `$notVar {$x} AAあ`},
+		{`"Tab\tand \"quote\""`, "Tab\tand &quot;quote&quot;"},
+		{`'a & b < c > d'`, `a &amp; b &lt; c &gt; d`},
+		{`'{{ $x }} {!! $y !!}'`, `@{{ $x }} @{!! $y !!}`},
+		{`'@if($x) mail@example.com'`, `@@if($x) mail@example.com`},
+		{`"<b>$name</b>"`, `&lt;b&gt;{{ $name }}&lt;/b&gt;`},
+		{`"price: 5$"`, `price: 5$`},
+		{"<<<EOT\n  row_{$i}\n  EOT", `row_{{ $i }}`},
+		{"<<<'EOT'\n  row_{$i}\n  EOT", `row_{$i}`},
+		{`'single $x'`, `single $x`},
+	}
+	for _, tt := range tests {
+		node, err := parsePHPExpr(tt.input)
+		if err != nil {
+			t.Errorf("parsePHPExpr(%q) error: %v", tt.input, err)
+			continue
+		}
+		if got, ok := node.bladeText(); !ok || got != tt.expected {
+			t.Errorf("bladeText(%s) = %q, %v, want %q", tt.input, got, ok, tt.expected)
+		}
+	}
+}
+
+func TestConvertTemplateInterpolatedStrings(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:     "Interpolated field name",
+			input:    `{{ Form::text("items[{$row->id}][qty]", $row->qty) }}`,
+			expected: `<input type="text" name="items[{{ $row->id }}][qty]" value="{{ $row->qty }}">`,
+		},
+		{
+			name:     "Simple variable in name and attribute",
+			input:    `{{ Form::text("option_$i", null, ['id' => "option-$i", 'class' => "col-{$size}"]) }}`,
+			expected: `<input type="text" name="option_{{ $i }}" value="" class="col-{{ $size }}" id="option-{{ $i }}">`,
+		},
+		{
+			name:     "Interpolated checkbox value",
+			input:    `{{ Form::checkbox('opts[]', "opt_$i", $checked) }}`,
+			expected: `<input type="checkbox" name="opts[]" value="opt_{{ $i }}" @if(in_array("opt_$i", (array)$checked)) checked @endif>`,
+		},
+		{
+			name:     "Interpolated file name and submit text",
+			input:    `{{ Form::file("files[$i]") }}{{ Form::submit("Save $label") }}`,
+			expected: `<input type="file" name="files[{{ $i }}]"><button type="submit">Save {{ $label }}</button>`,
+		},
+		{
+			name:     "Escapes in a label target",
+			input:    `{{ Form::label("a\$b", 'A', ['for' => "x\x41"]) }}`,
+			expected: `<label for="xA">{!! 'A' !!}</label>`,
+		},
+		{
+			name:     "Quotes in a literal attribute",
+			input:    `{{ Form::text('q', null, ['placeholder' => "Say \"hi\""]) }}`,
+			expected: `<input type="text" name="q" value="" placeholder="Say &quot;hi&quot;">`,
+		},
+		{
+			name:     "Quotes in an event handler become single quotes",
+			input:    `{!! Form::open(['route' => 'x', 'method' => 'POST', 'onsubmit' => 'return confirm("ok?")']) !!}`,
+			expected: "<form action=\"{{ route('x') }}\" method=\"POST\" onsubmit=\"return confirm('ok?')\">\n{{ csrf_field() }}",
+		},
+		{
+			name:     "Blade syntax in a literal is not evaluated",
+			input:    `{{ Form::text('q', null, ['placeholder' => '{{ $x }}', 'title' => '@csrf']) }}`,
+			expected: `<input type="text" name="q" value="" placeholder="@{{ $x }}" title="@@csrf">`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got, _ := convertTemplate(tt.input); got != tt.expected {
+				t.Errorf("convertTemplate() =\n%s\nwant:\n%s", got, tt.expected)
+			}
+		})
+	}
+}
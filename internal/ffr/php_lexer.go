@@ -0,0 +1,185 @@
+// php_lexer.go: Form:: の引数に現れる PHP 式の字句解析。
+package ffr
+
+import (
+	"fmt"
+	"strings"
+)
+
+// phpTokenKind は PHP のトークンの種類。
+type phpTokenKind int
+
+const (
+	phpTokenEOF      phpTokenKind = iota
+	phpTokenVariable              // $name
+	phpTokenName                  // 識別子・キーワード・名前空間付きの名前（\Foo\Bar）
+	phpTokenNumber                // 整数・浮動小数点数
+	phpTokenString                // '...' / "..." / ヒアドキュメント / Nowdoc
+	phpTokenOp                    // 演算子・区切り記号
+)
+
+// phpToken は PHP のトークン1つ分（src[pos:end]）。
+type phpToken struct {
+	kind phpTokenKind
+	text string
+	pos  int
+	end  int
+}
+
+// phpOperators は演算子・区切り記号（長いものから順に照合する）
+var phpOperators = []string{
+	"<=>", "===", "!==", "**=", "...", "??=", "<<=", ">>=", "?->",
+	"==", "!=", "<>", "<=", ">=", "&&", "||", "??", "->", "=>", "::", "++", "--",
+	"+=", "-=", "*=", "/=", ".=", "%=", "&=", "|=", "^=", "<<", ">>", "**",
+	"+", "-", "*", "/", "%", ".", "=", "<", ">", "!", "?", ":", "&", "|", "^", "~", "@",
+	"(", ")", "[", "]", "{", "}", ",", ";", "$",
+}
+
+// lexPHP は PHP 式のソースをトークン列に分割する（空白・コメントは読み飛ばす）。
+func lexPHP(src string) ([]phpToken, error) {
+	var tokens []phpToken
+	for i := 0; i < len(src); {
+		c := src[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\r' || c == '\n':
+			i++
+			continue
+		case strings.HasPrefix(src[i:], "/*"):
+			end := strings.Index(src[i+2:], "*/")
+			if end < 0 {
+				return nil, fmt.Errorf("コメントが閉じていません（%d 文字目）", i+1)
+			}
+			i += 2 + end + 2
+			continue
+		case strings.HasPrefix(src[i:], "//") || c == '#' && !strings.HasPrefix(src[i:], "#["):
+			for i < len(src) && src[i] != '\n' {
+				i++
+			}
+			continue
+		}
+
+		start := i
+		kind := phpTokenOp
+		switch {
+		case c == '$' && i+1 < len(src) && isPHPNameStart(src[i+1]):
+			kind = phpTokenVariable
+			i = scanPHPName(src, i+1)
+		case isPHPNameStart(c) || c == '\\' && i+1 < len(src) && isPHPNameStart(src[i+1]):
+			kind = phpTokenName
+			for i < len(src) && (src[i] == '\\' || isPHPNameStart(src[i])) {
+				i = scanPHPName(src, i+1)
+			}
+		case c >= '0' && c <= '9' || c == '.' && i+1 < len(src) && src[i+1] >= '0' && src[i+1] <= '9':
+			kind = phpTokenNumber
+			i = scanPHPNumber(src, i)
+		case c == '\'' || c == '"':
+			end, err := scanPHPQuoted(src, i)
+			if err != nil {
+				return nil, err
+			}
+			kind, i = phpTokenString, end
+		case strings.HasPrefix(src[i:], "<<<"):
+			end, err := scanPHPHeredoc(src, i)
+			if err != nil {
+				return nil, err
+			}
+			kind, i = phpTokenString, end
+		default:
+			op := ""
+			for _, candidate := range phpOperators {
+				if strings.HasPrefix(src[i:], candidate) {
+					op = candidate
+					break
+				}
+			}
+			if op == "" {
+				return nil, fmt.Errorf("解釈できない文字 %q があります（%d 文字目）", c, i+1)
+			}
+			i += len(op)
+		}
+		tokens = append(tokens, phpToken{kind: kind, text: src[start:i], pos: start, end: i})
+	}
+	return append(tokens, phpToken{kind: phpTokenEOF, pos: len(src), end: len(src)}), nil
+}
+
+// isPHPNameStart は識別子に使える文字かを返す（2文字目以降の数字は scanPHPName で扱う）。
+func isPHPNameStart(c byte) bool {
+	return c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= 0x80
+}
+
+// scanPHPName は i から始まる識別子の終わりを返す。
+func scanPHPName(src string, i int) int {
+	for i < len(src) && (isPHPNameStart(src[i]) || src[i] >= '0' && src[i] <= '9') {
+		i++
+	}
+	return i
+}
+
+// scanPHPNumber は i から始まる数値リテラルの終わりを返す。
+func scanPHPNumber(src string, i int) int {
+	for i < len(src) {
+		c := src[i]
+		switch {
+		case c >= '0' && c <= '9' || c == '_' || c == '.' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z':
+			i++
+		case (c == '+' || c == '-') && (src[i-1] == 'e' || src[i-1] == 'E') && !strings.HasPrefix(strings.ToLower(src[:i]), "0x"):
+			i++
+		default:
+			return i
+		}
+	}
+	return i
+}
+
+// scanPHPQuoted は i のクォートで始まる文字列リテラルの終わりを返す。
+func scanPHPQuoted(src string, i int) (int, error) {
+	quote := src[i]
+	for j := i + 1; j < len(src); j++ {
+		switch src[j] {
+		case '\\':
+			j++
+		case quote:
+			return j + 1, nil
+		}
+	}
+	return 0, fmt.Errorf("文字列が閉じていません（%d 文字目）", i+1)
+}
+
+// scanPHPHeredoc は i の <<< で始まるヒアドキュメント・Nowdoc の終わりを返す。
+func scanPHPHeredoc(src string, i int) (int, error) {
+	label, bodyStart, ok := heredocLabel(src[i:])
+	if !ok {
+		return 0, fmt.Errorf("ヒアドキュメントの開始が不正です（%d 文字目）", i+1)
+	}
+	bodyStart += i
+	// 終了ラベルは行頭（インデント可）にあり、直後に識別子の文字が続かない
+	for lineStart := bodyStart; lineStart <= len(src); {
+		line := strings.TrimLeft(src[lineStart:], " \t")
+		if strings.HasPrefix(line, label) {
+			end := len(src) - len(line) + len(label)
+			if end >= len(src) || !isPHPNameStart(src[end]) && (src[end] < '0' || src[end] > '9') {
+				return end, nil
+			}
+		}
+		next := strings.IndexByte(src[lineStart:], '\n')
+		if next < 0 {
+			break
+		}
+		lineStart += next + 1
+	}
+	return 0, fmt.Errorf("ヒアドキュメントが閉じていません（%d 文字目）", i+1)
+}
+
+// heredocLabel は <<<LABEL / <<<"LABEL" / <<<'LABEL' の行からラベルと本文の開始位置を返す。
+func heredocLabel(s string) (string, int, bool) {
+	newline := strings.IndexByte(s, '\n')
+	if newline < 0 {
+		return "", 0, false
+	}
+	label := strings.TrimSpace(s[len("<<<"):newline])
+	label = strings.Trim(label, `"'`)
+	if label == "" || scanPHPName(label, 0) != len(label) {
+		return "", 0, false
+	}
+	return label, newline + 1, true
+}
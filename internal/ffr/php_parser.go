@@ -0,0 +1,760 @@
+// php_parser.go: Form:: の引数を PHP 式の構文木（AST）に変換するパーサ。
+package ffr
+
+import (
+	"fmt"
+	"strings"
+)
+
+// phpNodeKind は構文木のノードの種類。
+type phpNodeKind int
+
+const (
+	phpString   phpNodeKind = iota // 文字列リテラル（Value は展開後の値）
+	phpNumber                      // 数値リテラル
+	phpConst                       // true / false / null / 定数 / クラス名
+	phpVariable                    // $name（Value は $ を除いた名前）
+	phpArray                       // [...] / array(...)
+	phpBinary                      // 二項演算（Value は演算子、Children は左右のオペランド）
+	phpUnary                       // 単項演算・キャスト（Value は演算子、Children[0] がオペランド）
+	phpTernary                     // 三項演算子（Children は条件・真・偽。?: では真が nil）
+	phpCall                        // 関数・メソッド呼び出し、new（Children[0] が呼び出し先）
+	phpProperty                    // ->name / ?->name / ::name（Value は演算子、Name はメンバ名）
+	phpIndex                       // $a['key']（Children は対象と添字。$a[] では添字が nil）
+	phpParen                       // 括弧で囲まれた式（Children[0] が中身）
+	phpClosure                     // function () {...} / fn () => ... / match (...) {...}（中身は解析しない）
+)
+
+// phpNode は PHP 式の構文木のノード。
+type phpNode struct {
+	Kind     phpNodeKind
+	Src      string // ノードに対応する元のソース
+	Pos      int    // Src の解析対象ソース内での開始位置
+	Value    string
+	Name     string          // phpProperty のメンバ名
+	Quote    byte            // phpString のクォート（' / " / ヒアドキュメントは <）
+	Interp   bool            // phpString が変数展開を含むか
+	Parts    []phpStringPart // 変数展開を含む phpString の、文字列と式の並び
+	Long     bool            // phpArray が array(...) 形式か
+	Items    []phpArrayItem  // phpArray の要素
+	Args     []phpArg        // phpCall の引数
+	Children []*phpNode
+}
+
+// phpArrayItem は配列リテラルの要素1つ（キーがなければ Key は nil）。
+type phpArrayItem struct {
+	Key    *phpNode
+	Value  *phpNode
+	Spread bool // ...$rest
+}
+
+// phpArg は関数呼び出しの引数1つ（Name は名前付き引数の名前）。
+type phpArg struct {
+	Name   string
+	Value  *phpNode
+	Spread bool
+}
+
+// phpBinaryPrecedence は二項演算子の優先順位（大きいほど強く結合する）
+var phpBinaryPrecedence = map[string]int{
+	"or": 1, "xor": 2, "and": 3,
+	"??": 7,
+	"||": 8, "&&": 9,
+	"|": 10, "^": 11, "&": 12,
+	"==": 13, "!=": 13, "===": 13, "!==": 13, "<>": 13, "<=>": 13,
+	"<": 14, "<=": 14, ">": 14, ">=": 14,
+	".":  15,
+	"<<": 16, ">>": 16,
+	"+": 17, "-": 17,
+	"*": 18, "/": 18, "%": 18,
+	"instanceof": 20,
+	"**":         21,
+}
+
+// 代入・三項演算子・単項演算子の優先順位
+const (
+	phpAssignPrecedence  = 5
+	phpTernaryPrecedence = 6
+	phpUnaryPrecedence   = 19
+)
+
+// phpAssignOperators は代入演算子
+var phpAssignOperators = map[string]bool{
+	"=": true, "+=": true, "-=": true, "*=": true, "/=": true, ".=": true, "%=": true,
+	"**=": true, "??=": true, "&=": true, "|=": true, "^=": true, "<<=": true, ">>=": true,
+}
+
+// phpCasts はキャスト演算子の型名
+var phpCasts = map[string]bool{
+	"int": true, "integer": true, "bool": true, "boolean": true, "float": true, "double": true,
+	"real": true, "string": true, "array": true, "object": true, "unset": true, "binary": true,
+}
+
+// phpParser は再帰下降で PHP 式を解析する。
+type phpParser struct {
+	src    string
+	tokens []phpToken
+	pos    int
+}
+
+// parsePHPExpr はソース全体を1つの PHP 式として解析する。
+func parsePHPExpr(src string) (*phpNode, error) {
+	p, err := newPHPParser(src)
+	if err != nil {
+		return nil, err
+	}
+	node, err := p.parseExpr(0)
+	if err != nil {
+		return nil, err
+	}
+	if err := p.expectEOF(); err != nil {
+		return nil, err
+	}
+	return node, nil
+}
+
+// parsePHPArgs はカンマ区切りの引数リスト（括弧の内側）を解析する。末尾のカンマと名前付き引数を受け付ける。
+func parsePHPArgs(src string) ([]phpArg, error) {
+	p, err := newPHPParser(src)
+	if err != nil {
+		return nil, err
+	}
+	args, err := p.parseArgs(phpTokenEOF, "")
+	if err != nil {
+		return nil, err
+	}
+	return args, p.expectEOF()
+}
+
+func newPHPParser(src string) (*phpParser, error) {
+	tokens, err := lexPHP(src)
+	if err != nil {
+		return nil, err
+	}
+	return &phpParser{src: src, tokens: tokens}, nil
+}
+
+func (p *phpParser) peek() phpToken {
+	return p.tokens[p.pos]
+}
+
+func (p *phpParser) peekAt(n int) phpToken {
+	if p.pos+n < len(p.tokens) {
+		return p.tokens[p.pos+n]
+	}
+	return p.tokens[len(p.tokens)-1]
+}
+
+func (p *phpParser) next() phpToken {
+	tok := p.tokens[p.pos]
+	if tok.kind != phpTokenEOF {
+		p.pos++
+	}
+	return tok
+}
+
+// isOp は次のトークンが演算子 op かを返す。
+func (p *phpParser) isOp(op string) bool {
+	tok := p.peek()
+	return tok.kind == phpTokenOp && tok.text == op
+}
+
+// isKeyword は次のトークンがキーワード word（大文字・小文字を区別しない）かを返す。
+func (p *phpParser) isKeyword(word string) bool {
+	tok := p.peek()
+	return tok.kind == phpTokenName && strings.EqualFold(tok.text, word)
+}
+
+func (p *phpParser) expectOp(op string) error {
+	if !p.isOp(op) {
+		return p.errorf("%q が必要です", op)
+	}
+	p.next()
+	return nil
+}
+
+func (p *phpParser) expectEOF() error {
+	if p.peek().kind != phpTokenEOF {
+		return p.errorf("式の後に余分な記述があります")
+	}
+	return nil
+}
+
+func (p *phpParser) errorf(format string, args ...interface{}) error {
+	tok := p.peek()
+	near := tok.text
+	if tok.kind == phpTokenEOF {
+		near = "終端"
+	}
+	return fmt.Errorf("PHP 式を解析できません: %s（%d 文字目 %q 付近）", fmt.Sprintf(format, args...), tok.pos+1, near)
+}
+
+// node は start 番目のトークンから直前のトークンまでを Src とするノードを作る。
+func (p *phpParser) node(kind phpNodeKind, start int) *phpNode {
+	end := p.tokens[p.pos-1].end
+	return &phpNode{Kind: kind, Src: p.src[p.tokens[start].pos:end], Pos: p.tokens[start].pos}
+}
+
+// parseExpr は優先順位 minPrec 以上の演算子からなる式を解析する。
+func (p *phpParser) parseExpr(minPrec int) (*phpNode, error) {
+	start := p.pos
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		tok := p.peek()
+		op := tok.text
+		if tok.kind == phpTokenName {
+			op = strings.ToLower(op)
+		} else if tok.kind != phpTokenOp {
+			return left, nil
+		}
+
+		switch {
+		case op == "?" && minPrec <= phpTernaryPrecedence:
+			p.next()
+			var then *phpNode
+			if !p.isOp(":") {
+				if then, err = p.parseExpr(phpAssignPrecedence); err != nil {
+					return nil, err
+				}
+			}
+			if err := p.expectOp(":"); err != nil {
+				return nil, err
+			}
+			otherwise, err := p.parseExpr(phpTernaryPrecedence + 1)
+			if err != nil {
+				return nil, err
+			}
+			left = p.withChildren(phpTernary, start, "?", left, then, otherwise)
+		case phpAssignOperators[op] && tok.kind == phpTokenOp && minPrec <= phpAssignPrecedence:
+			p.next()
+			right, err := p.parseExpr(phpAssignPrecedence)
+			if err != nil {
+				return nil, err
+			}
+			left = p.withChildren(phpBinary, start, op, left, right)
+		default:
+			prec, ok := phpBinaryPrecedence[op]
+			if !ok || prec < minPrec || tok.kind == phpTokenName && op != "and" && op != "or" && op != "xor" && op != "instanceof" {
+				return left, nil
+			}
+			p.next()
+			// ?? と ** は右結合、それ以外は左結合
+			nextPrec := prec + 1
+			if op == "??" || op == "**" {
+				nextPrec = prec
+			}
+			right, err := p.parseExpr(nextPrec)
+			if err != nil {
+				return nil, err
+			}
+			left = p.withChildren(phpBinary, start, op, left, right)
+		}
+	}
+}
+
+// withChildren は start からのソースを持つ演算子ノードを作る。
+func (p *phpParser) withChildren(kind phpNodeKind, start int, op string, children ...*phpNode) *phpNode {
+	n := p.node(kind, start)
+	n.Value = op
+	n.Children = children
+	return n
+}
+
+// parseUnary は前置演算子・キャストと、それに続く後置演算子付きの項を解析する。
+func (p *phpParser) parseUnary() (*phpNode, error) {
+	start := p.pos
+	tok := p.peek()
+	if tok.kind == phpTokenOp {
+		switch tok.text {
+		case "!", "-", "+", "~", "@", "&", "++", "--":
+			p.next()
+			operand, err := p.parseExpr(phpUnaryPrecedence)
+			if err != nil {
+				return nil, err
+			}
+			return p.withChildren(phpUnary, start, tok.text, operand), nil
+		case "(":
+			if cast := p.peekAt(1); cast.kind == phpTokenName && phpCasts[strings.ToLower(cast.text)] && p.peekAt(2).text == ")" {
+				p.pos += 3
+				operand, err := p.parseExpr(phpUnaryPrecedence)
+				if err != nil {
+					return nil, err
+				}
+				return p.withChildren(phpUnary, start, "("+strings.ToLower(cast.text)+")", operand), nil
+			}
+		}
+	}
+	if tok.kind == phpTokenName {
+		switch strings.ToLower(tok.text) {
+		case "new":
+			return p.parseNew()
+		case "clone", "print", "throw", "yield", "include", "include_once", "require", "require_once":
+			p.next()
+			operand, err := p.parseExpr(phpAssignPrecedence)
+			if err != nil {
+				return nil, err
+			}
+			return p.withChildren(phpUnary, start, strings.ToLower(tok.text), operand), nil
+		}
+	}
+	primary, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	return p.parsePostfix(primary, start)
+}
+
+// parsePrimary は変数・リテラル・配列・括弧・クロージャ等の項を解析する。
+func (p *phpParser) parsePrimary() (*phpNode, error) {
+	start := p.pos
+	tok := p.peek()
+	switch tok.kind {
+	case phpTokenVariable:
+		p.next()
+		n := p.node(phpVariable, start)
+		n.Value = tok.text[1:]
+		return n, nil
+	case phpTokenNumber:
+		p.next()
+		n := p.node(phpNumber, start)
+		n.Value = tok.text
+		return n, nil
+	case phpTokenString:
+		p.next()
+		n := stringNode(tok.text)
+		n.Pos = tok.pos
+		return n, nil
+	case phpTokenName:
+		switch strings.ToLower(tok.text) {
+		case "array", "list":
+			if p.peekAt(1).text == "(" {
+				p.pos += 2
+				return p.parseArray(start, ")", true)
+			}
+		case "function", "fn":
+			return p.parseClosure()
+		case "static":
+			if next := p.peekAt(1); next.kind == phpTokenName && (strings.EqualFold(next.text, "function") || strings.EqualFold(next.text, "fn")) {
+				p.next()
+				n, err := p.parseClosure()
+				if n != nil {
+					n.Src, n.Pos = p.src[p.tokens[start].pos:p.tokens[p.pos-1].end], p.tokens[start].pos
+				}
+				return n, err
+			}
+		case "match":
+			if p.peekAt(1).text == "(" {
+				return p.parseMatch()
+			}
+		}
+		p.next()
+		n := p.node(phpConst, start)
+		n.Value = tok.text
+		return n, nil
+	case phpTokenOp:
+		switch tok.text {
+		case "[":
+			p.next()
+			return p.parseArray(start, "]", false)
+		case "(":
+			p.next()
+			inner, err := p.parseExpr(0)
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expectOp(")"); err != nil {
+				return nil, err
+			}
+			return p.withChildren(phpParen, start, "", inner), nil
+		case "$":
+			// $$name / ${expr}
+			p.next()
+			inner, err := p.parsePrimary()
+			if err != nil {
+				return nil, err
+			}
+			return p.withChildren(phpUnary, start, "$", inner), nil
+		case "{":
+			p.next()
+			inner, err := p.parseExpr(0)
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expectOp("}"); err != nil {
+				return nil, err
+			}
+			return p.withChildren(phpParen, start, "{", inner), nil
+		}
+	}
+	return nil, p.errorf("式が必要です")
+}
+
+// parsePostfix はメンバアクセス・添字・呼び出し等の後置演算子を解析する。
+func (p *phpParser) parsePostfix(left *phpNode, start int) (*phpNode, error) {
+	for {
+		tok := p.peek()
+		if tok.kind != phpTokenOp {
+			return left, nil
+		}
+		switch tok.text {
+		case "[", "{":
+			if tok.text == "{" && left.Kind != phpVariable && left.Kind != phpIndex {
+				return left, nil
+			}
+			closer := "]"
+			if tok.text == "{" {
+				closer = "}"
+			}
+			p.next()
+			var index *phpNode
+			if !p.isOp(closer) {
+				var err error
+				if index, err = p.parseExpr(0); err != nil {
+					return nil, err
+				}
+			}
+			if err := p.expectOp(closer); err != nil {
+				return nil, err
+			}
+			left = p.withChildren(phpIndex, start, "", left, index)
+		case "->", "?->", "::":
+			p.next()
+			member := p.next()
+			n := &phpNode{Kind: phpProperty, Value: tok.text, Pos: p.tokens[start].pos, Children: []*phpNode{left}}
+			switch {
+			case member.kind == phpTokenName || member.kind == phpTokenVariable:
+				n.Name = member.text
+			case member.kind == phpTokenOp && member.text == "{":
+				inner, err := p.parseExpr(0)
+				if err != nil {
+					return nil, err
+				}
+				if err := p.expectOp("}"); err != nil {
+					return nil, err
+				}
+				n.Name = "{" + inner.Src + "}"
+			default:
+				p.pos--
+				return nil, p.errorf("メンバ名が必要です")
+			}
+			n.Src = p.src[p.tokens[start].pos:p.tokens[p.pos-1].end]
+			left = n
+		case "(":
+			p.next()
+			args, err := p.parseArgs(phpTokenOp, ")")
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expectOp(")"); err != nil {
+				return nil, err
+			}
+			n := p.withChildren(phpCall, start, "", left)
+			n.Args = args
+			left = n
+		case "++", "--":
+			p.next()
+			left = p.withChildren(phpUnary, start, tok.text+"post", left)
+		default:
+			return left, nil
+		}
+	}
+}
+
+// parseArgs は終端（closer）の手前までの引数リストを解析する。
+func (p *phpParser) parseArgs(closerKind phpTokenKind, closer string) ([]phpArg, error) {
+	var args []phpArg
+	atEnd := func() bool {
+		tok := p.peek()
+		return tok.kind == closerKind && (closer == "" || tok.text == closer)
+	}
+	for !atEnd() {
+		var arg phpArg
+		if p.isOp("...") {
+			p.next()
+			arg.Spread = true
+			// func(...) は第一級呼び出し可能構文
+			if atEnd() {
+				args = append(args, arg)
+				break
+			}
+		}
+		if name := p.peek(); name.kind == phpTokenName && p.peekAt(1).kind == phpTokenOp && p.peekAt(1).text == ":" {
+			p.pos += 2
+			arg.Name = name.text
+		}
+		value, err := p.parseExpr(0)
+		if err != nil {
+			return nil, err
+		}
+		arg.Value = value
+		args = append(args, arg)
+		if !p.isOp(",") {
+			break
+		}
+		p.next()
+	}
+	if !atEnd() {
+		return nil, p.errorf("引数の区切りが不正です")
+	}
+	return args, nil
+}
+
+// parseArray は開き括弧の後から closer までの配列要素を解析する。
+func (p *phpParser) parseArray(start int, closer string, long bool) (*phpNode, error) {
+	var items []phpArrayItem
+	for !p.isOp(closer) {
+		var item phpArrayItem
+		if p.isOp(",") {
+			// list() の空要素
+			p.next()
+			continue
+		}
+		if p.isOp("...") {
+			p.next()
+			item.Spread = true
+		}
+		value, err := p.parseExpr(0)
+		if err != nil {
+			return nil, err
+		}
+		if !item.Spread && p.isOp("=>") {
+			p.next()
+			item.Key = value
+			if value, err = p.parseExpr(0); err != nil {
+				return nil, err
+			}
+		}
+		item.Value = value
+		items = append(items, item)
+		if !p.isOp(",") {
+			break
+		}
+		p.next()
+	}
+	if err := p.expectOp(closer); err != nil {
+		return nil, err
+	}
+	n := p.node(phpArray, start)
+	n.Items = items
+	n.Long = long
+	return n, nil
+}
+
+// parseNew は new クラス名(引数) を解析する。
+func (p *phpParser) parseNew() (*phpNode, error) {
+	start := p.pos
+	p.next()
+	class := p.next()
+	if class.kind != phpTokenName && class.kind != phpTokenVariable {
+		p.pos--
+		return nil, p.errorf("クラス名が必要です")
+	}
+	n := p.node(phpCall, start)
+	n.Value = "new"
+	n.Children = []*phpNode{{Kind: phpConst, Src: class.text, Pos: class.pos, Value: class.text}}
+	if p.isOp("(") {
+		p.next()
+		args, err := p.parseArgs(phpTokenOp, ")")
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expectOp(")"); err != nil {
+			return nil, err
+		}
+		n.Args = args
+		n.Src = p.src[p.tokens[start].pos:p.tokens[p.pos-1].end]
+	}
+	return p.parsePostfix(n, start)
+}
+
+// parseClosure は function (...) use (...) {...} と fn (...) => 式 を読み飛ばして1つのノードにする。
+func (p *phpParser) parseClosure() (*phpNode, error) {
+	start := p.pos
+	arrow := strings.EqualFold(p.next().text, "fn")
+	if p.isOp("&") {
+		p.next()
+	}
+	if err := p.skipBalanced("(", ")"); err != nil {
+		return nil, err
+	}
+	if !arrow && p.isKeyword("use") {
+		p.next()
+		if err := p.skipBalanced("(", ")"); err != nil {
+			return nil, err
+		}
+	}
+	if p.isOp(":") {
+		// 戻り値の型宣言
+		p.next()
+		for p.peek().kind == phpTokenName || p.isOp("?") || p.isOp("|") || p.isOp("&") {
+			p.next()
+		}
+	}
+	if arrow {
+		if err := p.expectOp("=>"); err != nil {
+			return nil, err
+		}
+		if _, err := p.parseExpr(phpAssignPrecedence); err != nil {
+			return nil, err
+		}
+	} else if err := p.skipBalanced("{", "}"); err != nil {
+		return nil, err
+	}
+	n := p.node(phpClosure, start)
+	n.Value = "function"
+	if arrow {
+		n.Value = "fn"
+	}
+	return n, nil
+}
+
+// parseMatch は match (式) {...} を読み飛ばして1つのノードにする。
+func (p *phpParser) parseMatch() (*phpNode, error) {
+	start := p.pos
+	p.next()
+	if err := p.skipBalanced("(", ")"); err != nil {
+		return nil, err
+	}
+	if err := p.skipBalanced("{", "}"); err != nil {
+		return nil, err
+	}
+	n := p.node(phpClosure, start)
+	n.Value = "match"
+	return n, nil
+}
+
+// skipBalanced は open で始まり対応する close で終わるトークン列を読み飛ばす。
+func (p *phpParser) skipBalanced(open, close string) error {
+	if err := p.expectOp(open); err != nil {
+		return err
+	}
+	var stack []string
+	closers := map[string]string{"(": ")", "[": "]", "{": "}"}
+	stack = append(stack, close)
+	for len(stack) > 0 {
+		tok := p.next()
+		switch {
+		case tok.kind == phpTokenEOF:
+			return p.errorf("%q が閉じていません", open)
+		case tok.kind != phpTokenOp:
+		case closers[tok.text] != "":
+			stack = append(stack, closers[tok.text])
+		case tok.text == ")" || tok.text == "]" || tok.text == "}":
+			if stack[len(stack)-1] != tok.text {
+				p.pos--
+				return p.errorf("括弧の対応が不正です")
+			}
+			stack = stack[:len(stack)-1]
+		}
+	}
+	return nil
+}
+
+// stringNode は文字列トークンからリテラルのノードを作る。
+func stringNode(text string) *phpNode {
+	n := &phpNode{Kind: phpString, Src: text}
+	switch text[0] {
+	case '\'':
+		n.Quote = '\''
+		n.Value = unescapeSingleQuoted(text[1 : len(text)-1])
+	case '"':
+		n.Quote = '"'
+		n.Value, n.Parts = unescapeDoubleQuoted(text[1:len(text)-1], '"')
+		n.Interp = n.Parts != nil
+	default:
+		n.Quote = '<'
+		body, nowdoc := heredocBody(text)
+		if nowdoc {
+			n.Value = body
+		} else {
+			n.Value, n.Parts = unescapeDoubleQuoted(body, 0)
+			n.Interp = n.Parts != nil
+		}
+	}
+	return n
+}
+
+// unescapeSingleQuoted はシングルクォート文字列のエスケープ（\' と \\）を展開する。
+func unescapeSingleQuoted(s string) string {
+	if !strings.Contains(s, `\`) {
+		return s
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+1 < len(s) && (s[i+1] == '\'' || s[i+1] == '\\') {
+			i++
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
+
+// heredocBody はヒアドキュメントの本文（終了ラベルのインデントを除いたもの）と Nowdoc かを返す。
+func heredocBody(text string) (string, bool) {
+	header := text[:strings.IndexByte(text, '\n')]
+	nowdoc := strings.Contains(header, "'")
+	label, bodyStart, _ := heredocLabel(text)
+	body := text[bodyStart : len(text)-len(label)]
+	lastNewline := strings.LastIndexByte(body, '\n')
+	indent := ""
+	if lastNewline >= 0 {
+		indent = body[lastNewline+1:]
+		body = body[:lastNewline]
+	} else {
+		indent, body = body, ""
+	}
+	if indent != "" {
+		lines := strings.Split(body, "\n")
+		for i, line := range lines {
+			lines[i] = strings.TrimPrefix(line, indent)
+		}
+		body = strings.Join(lines, "\n")
+	}
+	return body, nowdoc
+}
+
+// stringLiteral は変数展開を含まない文字列リテラルの値を返す。
+func (n *phpNode) stringLiteral() (string, bool) {
+	if n == nil || n.Kind != phpString || n.Interp {
+		return "", false
+	}
+	return n.Value, true
+}
+
+// isConst は n が定数 name（true / false / null 等、大文字・小文字を区別しない）かを返す。
+func (n *phpNode) isConst(name string) bool {
+	return n != nil && n.Kind == phpConst && strings.EqualFold(n.Value, name)
+}
+
+// arrayValue は配列リテラルから文字列キー key の値を返す（なければ nil）。
+func (n *phpNode) arrayValue(key string) *phpNode {
+	if n == nil || n.Kind != phpArray {
+		return nil
+	}
+	for _, item := range n.Items {
+		if k, ok := item.Key.stringLiteral(); ok && k == key {
+			return item.Value
+		}
+	}
+	return nil
+}
+
+// operandSrc は n の後ろに ->name や ['key'] を続けて書けるソースを返す（必要なら括弧で囲む）。
+func (n *phpNode) operandSrc() string {
+	switch n.Kind {
+	case phpVariable, phpProperty, phpCall, phpIndex, phpParen:
+		return n.Src
+	}
+	return "(" + n.Src + ")"
+}
+
+// concatOperands は文字列連結（.）の連鎖を左から順のオペランドに展開する。
+func (n *phpNode) concatOperands() []*phpNode {
+	if n.Kind == phpBinary && n.Value == "." {
+		return append(n.Children[0].concatOperands(), n.Children[1].concatOperands()...)
+	}
+	return []*phpNode{n}
+}
@@ -0,0 +1,177 @@
+package ffr
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// dumpPHPNode はテスト用に構文木を S 式風の文字列にする。
+func dumpPHPNode(n *phpNode) string {
+	if n == nil {
+		return "_"
+	}
+	var children []string
+	for _, c := range n.Children {
+		children = append(children, dumpPHPNode(c))
+	}
+	switch n.Kind {
+	case phpString:
+		if n.Interp {
+			return fmt.Sprintf("interp(%q)", n.Value)
+		}
+		return fmt.Sprintf("%q", n.Value)
+	case phpNumber, phpConst:
+		return n.Value
+	case phpVariable:
+		return "$" + n.Value
+	case phpArray:
+		var items []string
+		for _, item := range n.Items {
+			switch {
+			case item.Spread:
+				items = append(items, "..."+dumpPHPNode(item.Value))
+			case item.Key != nil:
+				items = append(items, dumpPHPNode(item.Key)+"=>"+dumpPHPNode(item.Value))
+			default:
+				items = append(items, dumpPHPNode(item.Value))
+			}
+		}
+		return "[" + strings.Join(items, " ") + "]"
+	case phpCall:
+		var args []string
+		for _, arg := range n.Args {
+			s := dumpPHPNode(arg.Value)
+			if arg.Name != "" {
+				s = arg.Name + ":" + s
+			}
+			args = append(args, s)
+		}
+		return fmt.Sprintf("(call%s %s %s)", map[string]string{"new": "-new"}[n.Value], children[0], strings.Join(args, " "))
+	case phpProperty:
+		return fmt.Sprintf("(%s %s %s)", n.Value, children[0], n.Name)
+	case phpIndex:
+		return fmt.Sprintf("(idx %s)", strings.Join(children, " "))
+	case phpParen:
+		return "(paren " + children[0] + ")"
+	case phpClosure:
+		return "<" + n.Value + ">"
+	}
+	return fmt.Sprintf("(%s %s)", n.Value, strings.Join(children, " "))
+}
+
+func TestParsePHPExpr(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`'It\'s'`, `"It's"`},
+		{`"say \"hi\"\n"`, `"say \"hi\"\n"`},
+		{`"items[{$row->id}]"`, `interp("items[{$row->id}]")`},
+		{`'a' . $b . 'c'`, `(. (. "a" $b) "c")`},
+		{`1 + 2 . 'x'`, `(. (+ 1 2) "x")`},
+		{`$a ? 'b' : 'c'`, `(? $a "b" "c")`},
+		{`$a ?: $b ?? 'c'`, `(? $a _ (?? $b "c"))`},
+		{`!$user?->isAdmin() && $x`, `(&& (! (call (?-> $user isAdmin) )) $x)`},
+		{`$data['a']['b']`, `(idx (idx $data "a") "b")`},
+		{`['class' => 'x', 'disabled', ]`, `["class"=>"x" "disabled"]`},
+		{`array('route' => array('a', $id))`, `["route"=>["a" $id]]`},
+		{`[...$a, 'b' => [1, [2]]]`, `[...$a "b"=>[1 [2]]]`},
+		{`App\Foo::class`, `(:: App\Foo class)`},
+		{`\Carbon\Carbon::now()->year`, `(-> (call (:: \Carbon\Carbon now) ) year)`},
+		{`new DateTime('now')`, `(call-new DateTime "now")`},
+		{`(int) $x`, `((int) $x)`},
+		{`fn($x) => $x * 2`, `<fn>`},
+		{`function ($q) use ($id) { return $q->where('id', $id); }`, `<function>`},
+		{`match($s) { 'a' => 1, default => 2 }`, `<match>`},
+		{"<<<EOT\n    Hello\n      World\n    EOT", `"Hello\n  World"`},
+		{"<<<'EOT'\n$raw\nEOT", `"$raw"`},
+		{`route('users.show', ['id' => $u->id])`, `(call route "users.show" ["id"=>(-> $u id)])`},
+		{`__("auth.email") /* comment */`, `(call __ "auth.email")`},
+		{`$a = $b ?? null`, `(= $a (?? $b null))`},
+	}
+	for _, tt := range tests {
+		node, err := parsePHPExpr(tt.input)
+		if err != nil {
+			t.Errorf("parsePHPExpr(%q) error: %v", tt.input, err)
+			continue
+		}
+		if got := dumpPHPNode(node); got != tt.expected {
+			t.Errorf("parsePHPExpr(%q) = %s, want %s", tt.input, got, tt.expected)
+		}
+		if node.Src != strings.TrimSpace(strings.Split(tt.input, " /*")[0]) {
+			t.Errorf("parsePHPExpr(%q).Src = %q", tt.input, node.Src)
+		}
+	}
+}
+
+func TestParsePHPExprErrors(t *testing.T) {
+	for _, input := range []string{`'open`, `['a' => ]`, `$a $b`, `foo(`, `[1, 2))`, ``} {
+		if node, err := parsePHPExpr(input); err == nil {
+			t.Errorf("parsePHPExpr(%q) = %s, want error", input, dumpPHPNode(node))
+		}
+	}
+}
+
+func TestParsePHPArgs(t *testing.T) {
+	args, err := parsePHPArgs(`'email', null, options: ['class' => "a, b"],`)
+	if err != nil {
+		t.Fatalf("parsePHPArgs() error: %v", err)
+	}
+	var got []string
+	for _, arg := range args {
+		got = append(got, arg.Name+":"+arg.Value.Src)
+	}
+	expected := `:'email' :null options:['class' => "a, b"]`
+	if strings.Join(got, " ") != expected {
+		t.Errorf("parsePHPArgs() = %s, want %s", strings.Join(got, " "), expected)
+	}
+}
+
+func TestConvertTemplateReadsParsedArguments(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:     "Double-quoted values, escaped quotes and trailing comma",
+			input:    `{{ Form::text("email", null, ["class" => "form-control", "placeholder" => 'It\'s here',]) }}`,
+			expected: `<input type="text" name="email" value="" placeholder="It's here" class="form-control">`,
+		},
+		{
+			name:     "Comma inside an attribute value",
+			input:    `{{ Form::text('tags', null, ['class' => 'a, b']) }}`,
+			expected: `<input type="text" name="tags" value="" class="a, b">`,
+		},
+		{
+			name:  "Route with a null-safe parameter",
+			input: `{!! Form::open(['route' => ['users.update', $user?->id], 'class' => 'a']) !!}`,
+			expected: `<form action="{{ route('users.update', $user?->id) }}" method="POST" class="a">
+{{ csrf_field() }}`,
+		},
+		{
+			name:  "Url given as a helper call",
+			input: `{!! Form::open(['url' => url('/a', ['x' => 1]), 'id' => 'f']) !!}`,
+			expected: `<form action="{{ url('/a', ['x' => 1]) }}" method="POST" id="f">
+{{ csrf_field() }}`,
+		},
+		{
+			name:     "Arrow function argument",
+			input:    `{{ Form::hidden('ids', $items->map(fn($i) => $i->id)->implode(',')) }}`,
+			expected: `<input type="hidden" name="ids" value="{{ $items->map(fn($i) => $i->id)->implode(',') }}">`,
+		},
+		{
+			name:     "Unparsable arguments are left untouched",
+			input:    `{{ Form::text('a', $b $c) }}`,
+			expected: `{{ Form::text('a', $b $c) }}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got, _ := convertTemplate(tt.input); got != tt.expected {
+				t.Errorf("convertTemplate() =\n%s\nwant:\n%s", got, tt.expected)
+			}
+		})
+	}
+}
@@ -4,9 +4,12 @@ package ffr
 import (
 	"bufio"
 	"fmt"
+	"io"
 	"io/fs"
+	"log"
 	"os"
 	"path/filepath"
+	"runtime"
 	"strings"
 )
 
@@ -15,46 +18,319 @@ type ReplacementConfig struct {
 	IsFile         bool
 	ProcessedFiles []string
 	FileCount      int
+
+	// dry-run 関連（差分を表示するだけでファイルは書き換えない）
+	DryRun      bool
+	DiffContext int
+	ColorDiff   bool
+	PatchFile   string
+
+	// Check は CI 向けのチェックモード（メモリ上で変換し、終了コードで結果を返す）
+	Check bool
+
+	// 標準入力フィルタモード（標準入力を変換して標準出力へ書き出す）
+	Stdin         bool
+	StdinFilename string
+
+	// 走査対象の絞り込み（グロブは対象ディレクトリからの相対パス）
+	Include  []string
+	Exclude  []string
+	NoIgnore bool // .gitignore / .ffrignore と既定の除外を無効にする
+
+	// 機械可読なレポート（Report は形式、ReportFile が空なら標準出力へ書き出す）
+	Report     string
+	ReportFile string
+
+	// git の変更状況による絞り込み（GitChanged は比較する ref）
+	GitChanged string
+	GitStaged  bool
+	Force      bool            // 未コミットの変更があっても実行する
+	gitPaths   map[string]bool // 選択されたファイルの絶対パス（nil なら絞り込まない）
+
+	// Jobs は並列に変換するファイル数（0 なら GOMAXPROCS）
+	Jobs int
+
+	// ConfigPath は --config で指定された設定ファイル（空なら対象パスから探索する）
+	ConfigPath string
+
+	// Transaction は全ファイルを書き込むか1つも書き込まないかのどちらかにするモード
+	Transaction bool
+
+	// バックアップ（書き換え前の内容を BackupDir/<timestamp>/ に保存する）
+	Backup    bool
+	BackupDir string
+	backup    *backupRun
+
+	// Results は処理したファイルごとの変換結果（ProcessedFiles と同順）
+	Results []*FileResult
+
+	// 出力先（out は人間向けのメッセージと差分、logger はエラー。nil なら標準出力・標準エラー）
+	out    io.Writer
+	logger *log.Logger
+}
+
+// newReplacementConfig は既定値を設定した ReplacementConfig を返す。
+func newReplacementConfig() *ReplacementConfig {
+	return &ReplacementConfig{
+		ProcessedFiles: make([]string, 0),
+		DiffContext:    defaultDiffContext,
+		BackupDir:      defaultBackupDir,
+	}
+}
+
+// messages は人間向けのメッセージの出力先を返す。
+func (c *ReplacementConfig) messages() io.Writer {
+	if c.out == nil {
+		return os.Stdout
+	}
+	return c.out
+}
+
+// log はエラー等の診断メッセージの出力先を返す。
+func (c *ReplacementConfig) log() *log.Logger {
+	if c.logger == nil {
+		return log.Default()
+	}
+	return c.logger
+}
+
+// jobCount は並列に変換するワーカー数を返す。
+func (c *ReplacementConfig) jobCount() int {
+	if c.Jobs > 0 {
+		return c.Jobs
+	}
+	return runtime.GOMAXPROCS(0)
+}
+
+// writesFiles は変換結果をファイルへ書き戻すモードかを返す。
+func (c *ReplacementConfig) writesFiles() bool {
+	return !c.DryRun && !c.Check
+}
+
+// showsProgress は処理中のファイル名など進捗を表示するかを返す（チェックモードでは結果のみ表示）。
+func (c *ReplacementConfig) showsProgress() bool {
+	return !c.Check || c.DryRun
+}
+
+// FileResult は1ファイル分の変換前後の内容を保持する。
+type FileResult struct {
+	Path      string
+	Original  string
+	Converted string
+	Mode      fs.FileMode // 書き戻し時に維持する元ファイルのパーミッション
+	Warnings  []conversionWarning
+	Sources   sourceMap // 変換後の位置から変換前の位置を求める対応表
+}
+
+// Changed は変換によって内容が変わったかを返す。
+func (r *FileResult) Changed() bool {
+	return r.Original != r.Converted
 }
 
 // processBladeFiles はディレクトリ（または単一ファイル）を走査して置換処理を行う。
+// トランザクションモードでは全ファイルの変換が成功してからまとめて書き込む。
 func processBladeFiles(config *ReplacementConfig) error {
+	var err error
 	if config.IsFile {
-		return processSingleFile(config, config.TargetPath)
+		if isGitSelected(config, config.TargetPath) {
+			err = processSingleFile(config, config.TargetPath)
+		}
+	} else {
+		var paths []string
+		err = walkTargetFiles(config, func(path string) error {
+			paths = append(paths, path)
+			return nil
+		})
+		if err == nil {
+			err = processFiles(config, paths)
+		}
+	}
+	if err != nil {
+		return err
+	}
+	if config.Transaction && config.writesFiles() {
+		return commitTransaction(config)
 	}
+	return nil
+}
 
-	return filepath.WalkDir(config.TargetPath, func(path string, d fs.DirEntry, err error) error {
-		if err != nil {
-			return err
+// fileOutcome は1ファイル分の変換結果（ワーカーから集計側へ渡す）。
+type fileOutcome struct {
+	path          string
+	hasFormFacade bool
+	result        *FileResult
+	err           error
+}
+
+// processFiles は paths を config.jobCount() 個のワーカーで並列に変換し、
+// 結果の表示・書き込み・集計は paths の順に1つのゴルーチンで行う。
+// 変換済みで未集計の結果はワーカー数の2倍までに抑える。
+func processFiles(config *ReplacementConfig, paths []string) error {
+	if len(paths) == 0 {
+		return nil
+	}
+	jobs := config.jobCount()
+	if jobs > len(paths) {
+		jobs = len(paths)
+	}
+
+	outcomes := make([]chan fileOutcome, len(paths))
+	for i := range outcomes {
+		outcomes[i] = make(chan fileOutcome, 1)
+	}
+	next := make(chan int)
+	slots := make(chan struct{}, jobs*2)
+	done := make(chan struct{})
+	defer close(done)
+
+	go func() {
+		defer close(next)
+		for i := range paths {
+			select {
+			case slots <- struct{}{}:
+			case <-done:
+				return
+			}
+			select {
+			case next <- i:
+			case <-done:
+				return
+			}
 		}
-		if !d.IsDir() && strings.HasSuffix(path, ".blade.php") {
-			return processSingleFile(config, path)
+	}()
+	for w := 0; w < jobs; w++ {
+		go func() {
+			for i := range next {
+				outcomes[i] <- convertTarget(paths[i])
+			}
+		}()
+	}
+
+	for i := range paths {
+		outcome := <-outcomes[i]
+		<-slots
+		if err := applyOutcome(config, outcome); err != nil {
+			return err
 		}
-		return nil
-	})
+	}
+	return nil
 }
 
 // processSingleFile は1ファイルの置換と進捗集計を行う。
 func processSingleFile(config *ReplacementConfig, filePath string) error {
+	return applyOutcome(config, convertTarget(filePath))
+}
+
+// convertTarget は Form:: を含むファイルを読み込んでメモリ上で変換する（並列に呼び出してよい）。
+func convertTarget(filePath string) fileOutcome {
+	outcome := fileOutcome{path: filePath}
 	hasFormFacade, err := containsFormFacade(filePath)
 	if err != nil {
-		return fmt.Errorf("ファイル %s のチェックに失敗しました: %v", filePath, err)
+		outcome.err = fmt.Errorf("ファイル %s のチェックに失敗しました: %v", filePath, err)
+		return outcome
+	}
+	if !hasFormFacade {
+		return outcome
+	}
+	outcome.hasFormFacade = true
+	outcome.result, err = convertFile(filePath)
+	if err != nil {
+		outcome.err = fmt.Errorf("ファイル %s の処理に失敗しました: %v", filePath, err)
 	}
+	return outcome
+}
 
-	if hasFormFacade {
-		fmt.Printf("処理中: %s\n", filePath)
-		err := replaceFormPatterns(filePath)
-		if err != nil {
+// applyOutcome は変換結果の表示・書き込みと進捗集計を行う（集計側のゴルーチンからのみ呼び出す）。
+func applyOutcome(config *ReplacementConfig, outcome fileOutcome) error {
+	filePath := outcome.path
+	if !outcome.hasFormFacade {
+		return outcome.err
+	}
+	if config.showsProgress() {
+		fmt.Fprintf(config.messages(), "処理中: %s\n", filePath)
+	}
+	if outcome.err != nil {
+		return outcome.err
+	}
+	result := outcome.result
+	if config.showsProgress() {
+		for _, w := range result.Warnings {
+			fmt.Fprintf(config.messages(), " - %s: %s:%d:%d: Form::%s %s\n", w.label(), filePath, w.Line, w.Column, w.Method, w.Message)
+		}
+	}
+	if config.DryRun {
+		printFileDiff(config, result)
+	} else if config.writesFiles() && !config.Transaction {
+		if err := writeResultWithBackup(config, result); err != nil {
 			return fmt.Errorf("ファイル %s の処理に失敗しました: %v", filePath, err)
 		}
-		config.ProcessedFiles = append(config.ProcessedFiles, filePath)
-		config.FileCount++
-		fmt.Printf(" - 処理完了: %s\n", filePath)
+	}
+	config.ProcessedFiles = append(config.ProcessedFiles, filePath)
+	config.Results = append(config.Results, result)
+	config.FileCount++
+	if config.showsProgress() {
+		fmt.Fprintf(config.messages(), " - 処理完了: %s\n", filePath)
 	}
 	return nil
 }
 
-// containsFormFacade はファイル内に "Form::" が存在するかを高速に判定する。
+// convertFile はファイルを読み込み、変換結果をメモリ上で生成する（書き込みは行わない）。
+func convertFile(filePath string) (*FileResult, error) {
+	info, err := os.Stat(filePath)
+	if err != nil {
+		return nil, err
+	}
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+	original := string(content)
+	converted, warnings, sources := convertTemplateMapped(original)
+	return &FileResult{
+		Path:      filePath,
+		Original:  original,
+		Converted: converted,
+		Mode:      info.Mode(),
+		Warnings:  warnings,
+		Sources:   sources,
+	}, nil
+}
+
+// printFileDiff は dry-run 時に1ファイル分の差分を標準出力へ表示する。
+func printFileDiff(config *ReplacementConfig, result *FileResult) {
+	diff := unifiedDiff(diffPath(result.Path), result.Original, result.Converted, config.DiffContext)
+	if diff == "" {
+		fmt.Fprintln(config.messages(), " - 変更はありません")
+		return
+	}
+	if config.ColorDiff {
+		diff = colorizeDiff(diff)
+	}
+	fmt.Fprint(config.messages(), diff)
+}
+
+// writePatchFile は dry-run の変換結果をまとめて git apply 互換のパッチとして書き出す。
+func writePatchFile(config *ReplacementConfig) error {
+	var patch strings.Builder
+	for _, result := range config.Results {
+		patch.WriteString(unifiedDiff(diffPath(result.Path), result.Original, result.Converted, config.DiffContext))
+	}
+	return os.WriteFile(config.PatchFile, []byte(patch.String()), 0644)
+}
+
+// diffPath は差分ヘッダ用に、カレントディレクトリからの相対パス（スラッシュ区切り）を返す。
+func diffPath(path string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		if wd, err := os.Getwd(); err == nil {
+			if rel, err := filepath.Rel(wd, abs); err == nil {
+				path = rel
+			}
+		}
+	}
+	return filepath.ToSlash(path)
+}
+
+// containsFormFacade はファイル内に Form ファサードの呼び出し（Form:: / \Form:: / 別名）が存在するかを高速に判定する。
 func containsFormFacade(filePath string) (bool, error) {
 	file, err := os.Open(filePath)
 	if err != nil {
@@ -64,7 +340,7 @@ func containsFormFacade(filePath string) (bool, error) {
 
 	scanner := bufio.NewScanner(file)
 	for scanner.Scan() {
-		if strings.Contains(scanner.Text(), "Form::") {
+		if containsFacadeRef(scanner.Text()) {
 			return true, nil
 		}
 	}
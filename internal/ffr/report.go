@@ -0,0 +1,217 @@
+// report.go: ダッシュボード等から利用する機械可読な実行レポート（--report=json）。
+package ffr
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"strings"
+)
+
+// reportFormatJSON は --report で指定できる形式
+const reportFormatJSON = "json"
+
+// runReport は実行全体のレポート。
+type runReport struct {
+	Version string       `json:"version"`
+	Target  string       `json:"target"`
+	Mode    string       `json:"mode"`
+	Files   []fileReport `json:"files"`
+	Totals  reportTotals `json:"totals"`
+}
+
+// fileReport は1ファイル分のレポート。
+type fileReport struct {
+	Path        string            `json:"path"`
+	Changed     bool              `json:"changed"`
+	Converted   map[string]int    `json:"converted"`
+	BytesBefore int               `json:"bytes_before"`
+	BytesAfter  int               `json:"bytes_after"`
+	BytesDelta  int               `json:"bytes_delta"`
+	LinesBefore int               `json:"lines_before"`
+	LinesAfter  int               `json:"lines_after"`
+	LinesDelta  int               `json:"lines_delta"`
+	Remaining   []remainingFacade `json:"remaining"`
+	Warnings    []reportMessage   `json:"warnings"`
+	Skipped     []reportMessage   `json:"skipped"`
+}
+
+// reportMessage は警告・スキップ1件分（位置と Form:: のメソッド名、理由）。
+type reportMessage struct {
+	Line    int    `json:"line"`
+	Column  int    `json:"column"`
+	Method  string `json:"method"`
+	Message string `json:"message"`
+}
+
+// remainingFacade は変換後も残る Form:: の出現位置。
+type remainingFacade struct {
+	Line   int    `json:"line"`
+	Column int    `json:"column"`
+	Method string `json:"method"`
+	Text   string `json:"text"`
+}
+
+// reportTotals はレポートの合計値。
+type reportTotals struct {
+	Files        int            `json:"files"`
+	ChangedFiles int            `json:"changed_files"`
+	Conversions  int            `json:"conversions"`
+	Converted    map[string]int `json:"converted"`
+	Remaining    int            `json:"remaining"`
+	Warnings     int            `json:"warnings"`
+	Skipped      int            `json:"skipped"`
+	BytesDelta   int            `json:"bytes_delta"`
+	LinesDelta   int            `json:"lines_delta"`
+}
+
+// reportsToStdout はレポートを標準出力へ書き出すかを返す。
+func (c *ReplacementConfig) reportsToStdout() bool {
+	return c.Report != "" && c.ReportFile == ""
+}
+
+// runMode はレポートに記録する実行モードを返す。
+func (c *ReplacementConfig) runMode() string {
+	switch {
+	case c.Check:
+		return "check"
+	case c.DryRun:
+		return "dry-run"
+	default:
+		return "write"
+	}
+}
+
+// buildReport は処理結果からレポートを組み立てる。
+func buildReport(config *ReplacementConfig) *runReport {
+	target := config.TargetPath
+	if config.Stdin {
+		target = config.stdinName()
+	}
+	report := &runReport{
+		Version: version,
+		Target:  target,
+		Mode:    config.runMode(),
+		Files:   []fileReport{},
+		Totals:  reportTotals{Converted: map[string]int{}},
+	}
+	for _, result := range config.Results {
+		file := buildFileReport(result)
+		report.Files = append(report.Files, file)
+
+		totals := &report.Totals
+		totals.Files++
+		if file.Changed {
+			totals.ChangedFiles++
+		}
+		for method, count := range file.Converted {
+			totals.Converted[method] += count
+			totals.Conversions += count
+		}
+		totals.Remaining += len(file.Remaining)
+		totals.Warnings += len(file.Warnings)
+		totals.Skipped += len(file.Skipped)
+		totals.BytesDelta += file.BytesDelta
+		totals.LinesDelta += file.LinesDelta
+	}
+	return report
+}
+
+// buildFileReport は1ファイル分の変換前後を比較してレポートを作る。
+// 変換したメソッドは、変換前後の Form::メソッド名( の出現数の差から求める。
+func buildFileReport(result *FileResult) fileReport {
+	file := fileReport{
+		Path:        result.Path,
+		Changed:     result.Changed(),
+		Converted:   map[string]int{},
+		BytesBefore: len(result.Original),
+		BytesAfter:  len(result.Converted),
+		LinesBefore: len(splitLines(result.Original)),
+		LinesAfter:  len(splitLines(result.Converted)),
+		Remaining:   []remainingFacade{},
+		Warnings:    []reportMessage{},
+		Skipped:     []reportMessage{},
+	}
+	file.BytesDelta = file.BytesAfter - file.BytesBefore
+	file.LinesDelta = file.LinesAfter - file.LinesBefore
+
+	before := countFormMethods(result.Original)
+	after := countFormMethods(result.Converted)
+	for method, count := range before {
+		if converted := count - after[method]; converted > 0 {
+			file.Converted[method] = converted
+		}
+	}
+
+	// 変換時の警告・スキップがある位置は、その理由を優先して1件だけ報告する
+	warned := map[[2]int]bool{}
+	for _, w := range result.Warnings {
+		message := reportMessage{Line: w.Line, Column: w.Column, Method: w.Method, Message: w.Message}
+		if w.Skipped {
+			file.Skipped = append(file.Skipped, message)
+		} else {
+			file.Warnings = append(file.Warnings, message)
+		}
+		warned[[2]int{w.Line, w.Column}] = true
+	}
+	for _, occ := range result.remainingFacades() {
+		file.Remaining = append(file.Remaining, remainingFacade{
+			Line:   occ.Line,
+			Column: occ.Column,
+			Method: occ.Method,
+			Text:   occ.Text,
+		})
+		if !warned[[2]int{occ.Line, occ.Column}] {
+			file.Warnings = append(file.Warnings, remainingWarning(occ))
+		}
+	}
+	return file
+}
+
+// countFormMethods はテキスト中の Form:: 呼び出しをメソッドごとに数える。
+func countFormMethods(text string) map[string]int {
+	counts := map[string]int{}
+	for _, ref := range findFacadeRefs(text, 0, len(text)) {
+		method := leadingIdentifier(text[ref.end:])
+		if rest := strings.TrimLeft(text[ref.end+len(method):], " \t\r\n"); method != "" && strings.HasPrefix(rest, "(") {
+			counts[method]++
+		}
+	}
+	return counts
+}
+
+// remainingWarning は残存する Form:: についての警告を返す。
+func remainingWarning(occ facadeOccurrence) reportMessage {
+	warning := reportMessage{Line: occ.Line, Column: occ.Column, Method: occ.Method}
+	switch {
+	case occ.Method == "":
+		warning.Message = "Form:: の呼び出しを解析できませんでした"
+	case formHandlers[strings.ToLower(occ.Method)] != nil:
+		warning.Message = "対応メソッドですが変換できませんでした（引数の書式を確認してください）"
+	default:
+		warning.Message = "未対応のメソッドです"
+	}
+	return warning
+}
+
+// writeReport はレポートを JSON で w へ書き出す。
+func writeReport(config *ReplacementConfig, w io.Writer) error {
+	data, err := json.MarshalIndent(buildReport(config), "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(append(data, '\n'))
+	return err
+}
+
+// emitReport は --report-file 指定時はファイルへ、それ以外は stdout へレポートを書き出す。
+func emitReport(config *ReplacementConfig, stdout io.Writer) error {
+	if config.ReportFile == "" {
+		return writeReport(config, stdout)
+	}
+	var buf strings.Builder
+	if err := writeReport(config, &buf); err != nil {
+		return err
+	}
+	return os.WriteFile(config.ReportFile, []byte(buf.String()), 0644)
+}
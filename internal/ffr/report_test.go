@@ -0,0 +1,142 @@
+package ffr
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestBuildFileReport(t *testing.T) {
+	result := &FileResult{
+		Path: "edit.blade.php",
+		Original: "{!! Form::open(['route' => 'user.update', 'method' => 'POST']) !!}\n" +
+			"{{ Form::text('name') }}\n" +
+			"{{ Form::text('email') }}\n" +
+			"{{ Form::macro('userField', fn () => '') }}\n" +
+			"{!! Form::close() !!}\n",
+	}
+	result.Converted = convertFormPatterns(result.Original)
+
+	report := buildFileReport(result)
+	if !report.Changed {
+		t.Error("Changed should be true")
+	}
+	expectedConverted := map[string]int{"open": 1, "text": 2, "close": 1}
+	if !reflect.DeepEqual(report.Converted, expectedConverted) {
+		t.Errorf("Converted = %v, want %v", report.Converted, expectedConverted)
+	}
+	if report.BytesDelta != len(result.Converted)-len(result.Original) {
+		t.Errorf("BytesDelta = %d", report.BytesDelta)
+	}
+	// Form::open は CSRF の行が増える
+	if report.LinesBefore != 5 || report.LinesAfter != 6 || report.LinesDelta != 1 {
+		t.Errorf("lines = %d -> %d (%d), want 5 -> 6 (1)", report.LinesBefore, report.LinesAfter, report.LinesDelta)
+	}
+	expectedRemaining := []remainingFacade{
+		{Line: 5, Column: 4, Method: "macro", Text: "{{ Form::macro('userField', fn () => '') }}"},
+	}
+	if !reflect.DeepEqual(report.Remaining, expectedRemaining) {
+		t.Errorf("Remaining = %+v, want %+v", report.Remaining, expectedRemaining)
+	}
+	if !reflect.DeepEqual(report.Warnings, []reportMessage{{Line: 5, Column: 4, Method: "macro", Message: "未対応のメソッドです"}}) {
+		t.Errorf("Warnings = %v", report.Warnings)
+	}
+}
+
+func TestRemainingWarning(t *testing.T) {
+	tests := []struct {
+		occ      facadeOccurrence
+		expected reportMessage
+	}{
+		{facadeOccurrence{Line: 1, Column: 2, Method: "text"}, reportMessage{1, 2, "text", "対応メソッドですが変換できませんでした（引数の書式を確認してください）"}},
+		{facadeOccurrence{Line: 3, Column: 4, Method: "macro"}, reportMessage{3, 4, "macro", "未対応のメソッドです"}},
+		{facadeOccurrence{Line: 5, Column: 6}, reportMessage{5, 6, "", "Form:: の呼び出しを解析できませんでした"}},
+	}
+	for _, tt := range tests {
+		if result := remainingWarning(tt.occ); result != tt.expected {
+			t.Errorf("remainingWarning(%+v) = %+v, want %+v", tt.occ, result, tt.expected)
+		}
+	}
+}
+
+func TestRunWritesJSONReport(t *testing.T) {
+	useSettings(t, defaultSettings())
+	tempDir := t.TempDir()
+	files := map[string]string{
+		"a.blade.php": "{{ Form::text('a') }}\n{{ Form::label('a', 'A') }}\n",
+		"b.blade.php": "{{ Form::macro('x') }}\n",
+	}
+	for filename, content := range files {
+		if err := os.WriteFile(filepath.Join(tempDir, filename), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to create test file: %v", err)
+		}
+	}
+
+	// 標準出力には JSON だけが書き出され、メッセージは標準エラーへ回ることを確認する
+	var stdout, stderr strings.Builder
+	code := RunWith([]string{"form-facade-replacer", "--dry-run", "--report=json", tempDir}, strings.NewReader(""), &stdout, &stderr)
+	if code != 0 {
+		t.Fatalf("RunWith() = %d, want 0", code)
+	}
+	if !strings.Contains(stderr.String(), "dry-run が完了しました") {
+		t.Errorf("messages were not written to stderr:\n%s", stderr.String())
+	}
+
+	data := []byte(stdout.String())
+	var report runReport
+	if err := json.Unmarshal(data, &report); err != nil {
+		t.Fatalf("stdout is not a JSON report: %v\n%s", err, string(data))
+	}
+	if report.Mode != "dry-run" || report.Target != tempDir {
+		t.Errorf("mode/target = %s %s", report.Mode, report.Target)
+	}
+	expectedTotals := reportTotals{
+		Files:        2,
+		ChangedFiles: 1,
+		Conversions:  2,
+		Converted:    map[string]int{"text": 1, "label": 1},
+		Remaining:    1,
+		Warnings:     1,
+		BytesDelta:   report.Files[0].BytesDelta,
+		LinesDelta:   0,
+	}
+	if !reflect.DeepEqual(report.Totals, expectedTotals) {
+		t.Errorf("Totals = %+v, want %+v", report.Totals, expectedTotals)
+	}
+	if report.Files[1].Path != filepath.Join(tempDir, "b.blade.php") || len(report.Files[1].Remaining) != 1 {
+		t.Errorf("unexpected file report: %+v", report.Files[1])
+	}
+
+	// --report-file はファイルへ書き出す
+	reportPath := filepath.Join(t.TempDir(), "report.json")
+	if code := Run([]string{"form-facade-replacer", "--check", "--report-file", reportPath, tempDir}); code != exitCheckUnconverted {
+		t.Fatalf("Run() = %d, want %d", code, exitCheckUnconverted)
+	}
+	data, err := os.ReadFile(reportPath)
+	if err != nil {
+		t.Fatalf("report file was not written: %v", err)
+	}
+	if err := json.Unmarshal(data, &report); err != nil || report.Mode != "check" {
+		t.Errorf("invalid report file: %v\n%s", err, string(data))
+	}
+}
+
+func TestParseArgsReport(t *testing.T) {
+	config := newReplacementConfig()
+	if _, err := parseArgs([]string{"--report-file", "out.json", "views"}, config); err != nil {
+		t.Fatalf("parseArgs() error: %v", err)
+	}
+	if config.Report != reportFormatJSON || config.ReportFile != "out.json" {
+		t.Errorf("Report = %q, ReportFile = %q", config.Report, config.ReportFile)
+	}
+
+	if _, err := parseArgs([]string{"--report=xml", "views"}, newReplacementConfig()); err == nil {
+		t.Error("parseArgs() should reject unknown report formats")
+	}
+	if _, err := parseArgs([]string{"--stdin", "--report=json"}, newReplacementConfig()); err == nil {
+		t.Error("parseArgs() should reject a stdout report in stdin mode")
+	}
+}
@@ -0,0 +1,59 @@
+// stdin.go: 標準入力から読み込み標準出力へ書き出すフィルタモード。
+package ffr
+
+import (
+	"fmt"
+	"io"
+)
+
+// stdinDisplayName は --stdin-filename 未指定時に診断メッセージで使う名前。
+const stdinDisplayName = "<stdin>"
+
+// stdinName は標準入力の内容を指す表示名を返す。
+func (c *ReplacementConfig) stdinName() string {
+	if c.StdinFilename != "" {
+		return c.StdinFilename
+	}
+	return stdinDisplayName
+}
+
+// processStdin は in から読み込んだ Blade テンプレートを変換して out へ書き出す。
+// dry-run 時は変換結果の代わりに差分を、チェックモード時は何も書き出さない。
+func processStdin(config *ReplacementConfig, in io.Reader, out io.Writer) error {
+	content, err := io.ReadAll(in)
+	if err != nil {
+		return fmt.Errorf("標準入力の読み込みに失敗しました: %v", err)
+	}
+	original := string(content)
+	converted, warnings, sources := convertTemplateMapped(original)
+	result := &FileResult{
+		Path:      config.stdinName(),
+		Original:  original,
+		Converted: converted,
+		Warnings:  warnings,
+		Sources:   sources,
+	}
+	for _, w := range warnings {
+		config.log().Printf("%s: %s:%d:%d: Form::%s %s", w.label(), result.Path, w.Line, w.Column, w.Method, w.Message)
+	}
+	config.ProcessedFiles = append(config.ProcessedFiles, result.Path)
+	config.Results = append(config.Results, result)
+	config.FileCount++
+
+	switch {
+	case config.Check:
+		return nil
+	case config.DryRun:
+		diff := unifiedDiff(diffPath(result.Path), result.Original, result.Converted, config.DiffContext)
+		if config.ColorDiff {
+			diff = colorizeDiff(diff)
+		}
+		_, err = io.WriteString(out, diff)
+	default:
+		_, err = io.WriteString(out, result.Converted)
+	}
+	if err != nil {
+		return fmt.Errorf("標準出力への書き込みに失敗しました: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+package ffr
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestProcessStdin(t *testing.T) {
+	input := "<div>\n{!! Form::text('name', $user->name) !!}\n</div>\n"
+
+	tests := []struct {
+		name     string
+		setup    func(config *ReplacementConfig)
+		expected string
+	}{
+		{
+			name:     "Converted template is written to output",
+			setup:    func(config *ReplacementConfig) {},
+			expected: "<div>\n<input type=\"text\" name=\"name\" value=\"{{ $user->name }}\">\n</div>\n",
+		},
+		{
+			name: "Diff mode writes a unified diff using stdin filename",
+			setup: func(config *ReplacementConfig) {
+				config.DryRun = true
+				config.StdinFilename = "edit.blade.php"
+			},
+			expected: `diff --git a/edit.blade.php b/edit.blade.php
+--- a/edit.blade.php
++++ b/edit.blade.php
+@@ -1,3 +1,3 @@
+ <div>
+-{!! Form::text('name', $user->name) !!}
++<input type="text" name="name" value="{{ $user->name }}">
+ </div>
+`,
+		},
+		{
+			name: "Check mode writes nothing",
+			setup: func(config *ReplacementConfig) {
+				config.Check = true
+			},
+			expected: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := newReplacementConfig()
+			config.Stdin = true
+			tt.setup(config)
+
+			var out strings.Builder
+			if err := processStdin(config, strings.NewReader(input), &out); err != nil {
+				t.Fatalf("processStdin() error: %v", err)
+			}
+			if out.String() != tt.expected {
+				t.Errorf("processStdin() wrote:\n%s\nwant:\n%s", out.String(), tt.expected)
+			}
+			if len(config.Results) != 1 || config.Results[0].Path != config.stdinName() {
+				t.Errorf("unexpected results: %+v", config.Results)
+			}
+		})
+	}
+}
+
+func TestParseArgsStdin(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		wantErr bool
+	}{
+		{name: "Dash argument", args: []string{"-"}},
+		{name: "Stdin flag with filename", args: []string{"--stdin", "--stdin-filename", "views/edit.blade.php"}},
+		{name: "Stdin with target path", args: []string{"-", "views"}, wantErr: true},
+		{name: "Stdin filename without stdin", args: []string{"--stdin-filename=x.blade.php", "views"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := newReplacementConfig()
+			_, err := parseArgs(tt.args, config)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseArgs() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !config.Stdin {
+				t.Error("expected stdin mode to be enabled")
+			}
+		})
+	}
+}
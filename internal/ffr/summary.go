@@ -3,30 +3,38 @@ package ffr
 
 import (
 	"fmt"
-	"io/fs"
+	"io"
 	"os"
-	"path/filepath"
 	"sort"
 	"strings"
 )
 
 // printSummary は処理件数・処理済みファイルと残存Form::パターンを表示する。
 func printSummary(config *ReplacementConfig) {
-	fmt.Println()
-	fmt.Println("=== 置換結果サマリー ===")
+	fmt.Fprintln(config.messages())
+	fmt.Fprintln(config.messages(), "=== 置換結果サマリー ===")
 	if config.IsFile {
-		fmt.Printf("対象ファイル: %s\n", config.TargetPath)
+		fmt.Fprintf(config.messages(), "対象ファイル: %s\n", config.TargetPath)
 	} else {
-		fmt.Printf("対象ディレクトリ: %s\n", config.TargetPath)
+		fmt.Fprintf(config.messages(), "対象ディレクトリ: %s\n", config.TargetPath)
 	}
-	fmt.Printf("処理したファイル数: %d\n", config.FileCount)
-	fmt.Println()
+	fmt.Fprintf(config.messages(), "処理したファイル数: %d\n", config.FileCount)
+	if config.DryRun {
+		fmt.Fprintf(config.messages(), "変更予定のファイル数: %d（dry-run のため書き換えていません）\n", countChangedResults(config.Results))
+	}
+	fmt.Fprintln(config.messages())
 	if len(config.ProcessedFiles) > 0 {
-		fmt.Println("=== 処理済みファイル ===")
+		fmt.Fprintln(config.messages(), "=== 処理済みファイル ===")
 		for _, file := range config.ProcessedFiles {
-			fmt.Printf(" - %s\n", file)
+			fmt.Fprintf(config.messages(), " - %s\n", file)
 		}
-		fmt.Println()
+		fmt.Fprintln(config.messages())
+	}
+	if config.DryRun {
+		printRemainingInResults(config.messages(), config.Results)
+		fmt.Fprintln(config.messages())
+		fmt.Fprintln(config.messages(), "dry-run が完了しました（ファイルは変更されていません）")
+		return
 	}
 	var remainingFiles []string
 	if config.IsFile {
@@ -34,35 +42,31 @@ func printSummary(config *ReplacementConfig) {
 			remainingFiles = []string{config.TargetPath}
 		}
 	} else {
-		remainingFiles = findRemainingFormFacades(config.TargetPath)
+		remainingFiles = findRemainingFormFacades(config)
 	}
 	if len(remainingFiles) > 0 {
-		fmt.Println("=== Form facadeが残存するファイル ===")
+		fmt.Fprintln(config.messages(), "=== Form facadeが残存するファイル ===")
 		for _, file := range remainingFiles {
-			fmt.Println(file)
+			fmt.Fprintln(config.messages(), file)
 		}
-		fmt.Println()
-		fmt.Println("=== 残存するForm facadeパターン ===")
-		showRemainingPatterns(remainingFiles)
+		fmt.Fprintln(config.messages())
+		fmt.Fprintln(config.messages(), "=== 残存するForm facadeパターン ===")
+		showRemainingPatterns(config.messages(), remainingFiles)
 	} else {
-		fmt.Println("Form facadeを含むファイルは見つかりませんでした（置換完了）")
+		fmt.Fprintln(config.messages(), "Form facadeを含むファイルは見つかりませんでした（置換完了）")
 	}
-	fmt.Println()
-	fmt.Println("置換処理が完了しました！")
+	fmt.Fprintln(config.messages())
+	fmt.Fprintln(config.messages(), "置換処理が完了しました！")
 }
 
 // findRemainingFormFacades は対象ディレクトリ配下で Form:: を含むファイルを列挙する。
-func findRemainingFormFacades(targetDir string) []string {
+// 処理時と同じ include/exclude・除外設定ファイルを適用し、意図的に除外したファイルは含めない。
+func findRemainingFormFacades(config *ReplacementConfig) []string {
 	var remainingFiles []string
-	filepath.WalkDir(targetDir, func(path string, d fs.DirEntry, err error) error {
-		if err != nil {
-			return err
-		}
-		if !d.IsDir() && strings.HasSuffix(path, ".blade.php") {
-			hasFormFacade, err := containsFormFacade(path)
-			if err == nil && hasFormFacade {
-				remainingFiles = append(remainingFiles, path)
-			}
+	walkTargetFiles(config, func(path string) error {
+		hasFormFacade, err := containsFormFacade(path)
+		if err == nil && hasFormFacade {
+			remainingFiles = append(remainingFiles, path)
 		}
 		return nil
 	})
@@ -71,17 +75,77 @@ func findRemainingFormFacades(targetDir string) []string {
 }
 
 // showRemainingPatterns は指定ファイル群の行単位で残存 Form:: パターンを出力する。
-func showRemainingPatterns(files []string) {
+func showRemainingPatterns(w io.Writer, files []string) {
 	for _, file := range files {
 		content, err := os.ReadFile(file)
 		if err != nil {
 			continue
 		}
-		lines := strings.Split(string(content), "\n")
-		for i, line := range lines {
-			if strings.Contains(line, "Form::") {
-				fmt.Printf("%s:%d:%s\n", file, i+1, strings.TrimSpace(line))
+		printRemainingLines(w, file, string(content))
+	}
+}
+
+// printRemainingLines は1ファイル分の内容から Form:: を含む行を出力する。
+func printRemainingLines(w io.Writer, file, content string) {
+	lines := strings.Split(content, "\n")
+	for i, line := range lines {
+		if containsFacadeRef(line) {
+			fmt.Fprintf(w, "%s:%d:%s\n", file, i+1, strings.TrimSpace(line))
+		}
+	}
+}
+
+// printRemainingInResults は dry-run の変換結果（メモリ上）に残る Form:: パターンを出力する。
+func printRemainingInResults(w io.Writer, results []*FileResult) {
+	var remaining []*FileResult
+	for _, result := range results {
+		if containsFacadeRef(result.Converted) {
+			remaining = append(remaining, result)
+		}
+	}
+	if len(remaining) == 0 {
+		fmt.Fprintln(w, "変換後に Form facade が残るファイルはありません")
+		return
+	}
+	fmt.Fprintln(w, "=== 変換後も Form facade が残るファイル ===")
+	for _, result := range remaining {
+		fmt.Fprintln(w, result.Path)
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "=== 残存するForm facadeパターン ===")
+	for _, result := range remaining {
+		printRemainingResultLines(w, result)
+	}
+}
+
+// printRemainingResultLines は変換結果に残る Form:: を含む行を、変換前のファイルの行番号と内容で出力する。
+// 前の呼び出しが複数行に展開されても、元のファイルで該当行を探せるようにするため。
+func printRemainingResultLines(w io.Writer, result *FileResult) {
+	if result.Sources == nil {
+		printRemainingLines(w, result.Path, result.Converted)
+		return
+	}
+	printed := map[int]bool{}
+	lineStart := 0
+	for _, line := range strings.Split(result.Converted, "\n") {
+		if refs := findFacadeRefs(line, 0, len(line)); len(refs) > 0 {
+			offset := result.Sources.originalOffset(result.Original, result.Converted, lineStart+refs[0].start)
+			if number, _ := offsetPosition(result.Original, offset); !printed[number] {
+				printed[number] = true
+				fmt.Fprintf(w, "%s:%d:%s\n", result.Path, number, strings.TrimSpace(lineAt(result.Original, offset)))
 			}
 		}
+		lineStart += len(line) + 1
+	}
+}
+
+// countChangedResults は変換で内容が変わるファイル数を返す。
+func countChangedResults(results []*FileResult) int {
+	count := 0
+	for _, result := range results {
+		if result.Changed() {
+			count++
+		}
 	}
+	return count
 }
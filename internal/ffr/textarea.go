@@ -9,22 +9,7 @@ import (
 // --- Textarea ---
 // replaceFormTextarea は Blade 内の Form::textarea(...) を HTML に置換する。
 func replaceFormTextarea(text string) string {
-	patterns := []string{
-		`(?s)\{\!\!\s*Form::textarea\(\s*(.*?)\s*\)\s*\!\!\}`,
-		`(?s)\{\{\s*Form::textarea\(\s*(.*?)\s*\)\s*\}\}`,
-	}
-	for _, pattern := range patterns {
-		re := regexCache.GetRegex(pattern)
-		text = re.ReplaceAllStringFunc(text, func(match string) string {
-			fullMatch := re.FindStringSubmatch(match)
-			if len(fullMatch) > 1 {
-				params := extractParamsBalanced(fullMatch[1])
-				return processFormTextarea(params)
-			}
-			return match
-		})
-	}
-	return text
+	return replaceFormMethod(text, "textarea")
 }
 
 // processFormTextarea は rows/placeholder など属性と値を整形し最終HTMLを生成する。
@@ -38,13 +23,14 @@ func processFormTextarea(params []string) string {
 		value = params[1]
 	}
 	attrProcessor := &AttributeProcessor{
-		Order: []string{"cols", "rows", "placeholder", "class"},
-		Patterns: map[string]string{
-			"cols":        `'cols'\s*=>\s*(\d+)`,
-			"rows":        `'rows'\s*=>\s*(?:'([^']+)'|(\d+))`,
-			"placeholder": `'placeholder'\s*=>\s*'([^']+)'`,
-			"class":       `'class'\s*=>\s*'([^']+)'`,
+		Order: attributeOrder("textarea", []string{"cols", "rows", "placeholder", "class"}),
+		Values: map[string]attrValue{
+			"cols":        attrText,
+			"rows":        attrText,
+			"placeholder": attrText,
+			"class":       attrText,
 		},
+		Skip: []string{"name", "value"},
 	}
 	extraAttrs := ""
 	if len(params) > 2 {
@@ -0,0 +1,184 @@
+// writer.go: 一時ファイル + rename による安全なファイル書き込みとトランザクション処理。
+package ffr
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// stagedFile は同じディレクトリの一時ファイルに書き出し済みで rename を待つ書き込み。
+type stagedFile struct {
+	path    string
+	tmpPath string
+}
+
+// stageFile は path と同じディレクトリに一時ファイルを作成し、data を書き込んで mode を設定する。
+// 同一ファイルシステム上に置くことで、後続の rename をアトミックにする。
+func stageFile(path string, data []byte, mode fs.FileMode) (*stagedFile, error) {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".ffr-*")
+	if err != nil {
+		return nil, err
+	}
+	staged := &stagedFile{path: path, tmpPath: tmp.Name()}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		staged.discard()
+		return nil, err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		staged.discard()
+		return nil, err
+	}
+	if err := tmp.Close(); err != nil {
+		staged.discard()
+		return nil, err
+	}
+	// CreateTemp は 0600 で作成するため元のパーミッションに戻す
+	if err := os.Chmod(staged.tmpPath, mode.Perm()); err != nil {
+		staged.discard()
+		return nil, err
+	}
+	return staged, nil
+}
+
+// commit は一時ファイルを rename して本来のパスを置き換える。
+func (s *stagedFile) commit() error {
+	if err := os.Rename(s.tmpPath, s.path); err != nil {
+		s.discard()
+		return err
+	}
+	return nil
+}
+
+// discard は一時ファイルを削除する。
+func (s *stagedFile) discard() {
+	os.Remove(s.tmpPath)
+}
+
+// writeFileAtomic は一時ファイル経由で path を data に置き換える。
+func writeFileAtomic(path string, data []byte, mode fs.FileMode) error {
+	staged, err := stageFile(path, data, mode)
+	if err != nil {
+		return err
+	}
+	return staged.commit()
+}
+
+// fileMode は既存ファイルのパーミッションを返す（取得できない場合は 0644）。
+func fileMode(path string) fs.FileMode {
+	if info, err := os.Stat(path); err == nil {
+		return info.Mode()
+	}
+	return 0644
+}
+
+// verifyUnchanged は読み込み後にファイルが他から変更されていないかを確認する。
+func verifyUnchanged(result *FileResult) error {
+	current, err := os.ReadFile(result.Path)
+	if err != nil {
+		return err
+	}
+	if string(current) != result.Original {
+		return fmt.Errorf("読み込み後に他のプロセスによって変更されています")
+	}
+	return nil
+}
+
+// writeResult は変換結果をアトミックにファイルへ書き戻す（内容が変わらない場合は何もしない）。
+func writeResult(result *FileResult) error {
+	return writeResultWithBackup(nil, result)
+}
+
+// writeResultWithBackup は変更チェックの後にバックアップを記録してから書き戻す（config.Backup の場合）。
+// 書き込みに失敗した場合は記録を取り消し、restore に書き込んでいないファイルを残さない。
+func writeResultWithBackup(config *ReplacementConfig, result *FileResult) error {
+	if !result.Changed() {
+		return nil
+	}
+	staged, err := stageFile(result.Path, []byte(result.Converted), result.Mode)
+	if err != nil {
+		return err
+	}
+	if err := verifyUnchanged(result); err != nil {
+		staged.discard()
+		return err
+	}
+	if config == nil || !config.Backup {
+		return staged.commit()
+	}
+	recorded := backupCount(config)
+	if err := backupResult(config, result); err != nil {
+		staged.discard()
+		return err
+	}
+	if err := staged.commit(); err != nil {
+		return forgetBackups(config, recorded, err)
+	}
+	return nil
+}
+
+// commitTransaction は変換結果をすべて書き込むか、1つも書き込まないかのどちらかにする。
+// 全ファイルの一時ファイル作成と変更チェックが成功してから rename し、
+// rename の途中で失敗した場合は置き換え済みのファイルを元の内容に戻す。
+func commitTransaction(config *ReplacementConfig) error {
+	var staged []*stagedFile
+	var pending []*FileResult
+	discardAll := func() {
+		for _, s := range staged {
+			s.discard()
+		}
+	}
+
+	// 1. 一時ファイルへ書き出す（この段階では既存ファイルに一切触れない）
+	for _, result := range config.Results {
+		if !result.Changed() {
+			continue
+		}
+		s, err := stageFile(result.Path, []byte(result.Converted), result.Mode)
+		if err != nil {
+			discardAll()
+			return fmt.Errorf("ファイル %s の書き込み準備に失敗しました: %v", result.Path, err)
+		}
+		staged = append(staged, s)
+		pending = append(pending, result)
+	}
+
+	// 2. 読み込み後に変更されたファイルがないか確認する
+	for _, result := range pending {
+		if err := verifyUnchanged(result); err != nil {
+			discardAll()
+			return fmt.Errorf("ファイル %s の処理に失敗しました: %v", result.Path, err)
+		}
+	}
+
+	// 3. バックアップを記録してから rename する（失敗した場合はこの書き込みの記録をすべて取り消す）
+	recorded := backupCount(config)
+	for i, result := range pending {
+		if config.Backup {
+			if err := backupResult(config, result); err != nil {
+				discardAll()
+				return forgetBackups(config, recorded, rollbackTransaction(pending[:i], err))
+			}
+		}
+		if err := staged[i].commit(); err != nil {
+			for _, s := range staged[i+1:] {
+				s.discard()
+			}
+			return forgetBackups(config, recorded, rollbackTransaction(pending[:i], fmt.Errorf("ファイル %s の書き込みに失敗しました: %v", result.Path, err)))
+		}
+	}
+	return nil
+}
+
+// rollbackTransaction は置き換え済みのファイルを元の内容に戻し、元のエラーを返す。
+func rollbackTransaction(committed []*FileResult, cause error) error {
+	for _, result := range committed {
+		if err := writeFileAtomic(result.Path, []byte(result.Original), result.Mode); err != nil {
+			return fmt.Errorf("%v（さらに %s のロールバックに失敗しました: %v）", cause, result.Path, err)
+		}
+	}
+	return fmt.Errorf("%v（書き込み済みの %d ファイルを元に戻しました）", cause, len(committed))
+}
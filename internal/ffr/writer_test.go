@@ -0,0 +1,164 @@
+package ffr
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestWriteResultPreservesMode(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("file permissions are not preserved on Windows")
+	}
+	tempDir := t.TempDir()
+	testFile := filepath.Join(tempDir, "form.blade.php")
+	if err := os.WriteFile(testFile, []byte("{{ Form::text('name') }}"), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+	if err := os.Chmod(testFile, 0775); err != nil {
+		t.Fatalf("Failed to chmod: %v", err)
+	}
+
+	if err := replaceFormPatterns(testFile); err != nil {
+		t.Fatalf("replaceFormPatterns() error: %v", err)
+	}
+
+	info, err := os.Stat(testFile)
+	if err != nil {
+		t.Fatalf("Failed to stat: %v", err)
+	}
+	if info.Mode().Perm() != 0775 {
+		t.Errorf("mode = %v, want 0775", info.Mode().Perm())
+	}
+	assertNoTempFiles(t, tempDir)
+}
+
+func TestWriteResultDetectsConcurrentModification(t *testing.T) {
+	tempDir := t.TempDir()
+	testFile := filepath.Join(tempDir, "form.blade.php")
+	if err := os.WriteFile(testFile, []byte("{{ Form::text('name') }}"), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	result, err := convertFile(testFile)
+	if err != nil {
+		t.Fatalf("convertFile() error: %v", err)
+	}
+	// 読み込み後に別のプロセスが書き換えた状況
+	edited := "{{ Form::text('edited') }}"
+	if err := os.WriteFile(testFile, []byte(edited), 0644); err != nil {
+		t.Fatalf("Failed to edit file: %v", err)
+	}
+
+	if err := writeResult(result); err == nil {
+		t.Error("writeResult() should fail when the file changed after reading")
+	}
+	content, _ := os.ReadFile(testFile)
+	if string(content) != edited {
+		t.Errorf("file was overwritten:\n%s", string(content))
+	}
+	assertNoTempFiles(t, tempDir)
+}
+
+func TestTransactionLeavesFilesUntouchedOnFailure(t *testing.T) {
+	tempDir := t.TempDir()
+	files := map[string]string{
+		"a.blade.php": "{{ Form::text('a') }}",
+		"b.blade.php": "{{ Form::text('b') }}",
+	}
+	for filename, content := range files {
+		if err := os.WriteFile(filepath.Join(tempDir, filename), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to create test file %s: %v", filename, err)
+		}
+	}
+	// 走査順で最後になる読み込めないファイル（リンク切れのシンボリックリンク）
+	if err := os.Symlink(filepath.Join(tempDir, "missing"), filepath.Join(tempDir, "z.blade.php")); err != nil {
+		t.Skipf("symlinks are not supported: %v", err)
+	}
+
+	config := newReplacementConfig()
+	config.TargetPath = tempDir
+	config.Transaction = true
+	if err := processBladeFiles(config); err == nil {
+		t.Fatal("processBladeFiles() should fail")
+	}
+
+	for filename, content := range files {
+		result, _ := os.ReadFile(filepath.Join(tempDir, filename))
+		if string(result) != content {
+			t.Errorf("%s was modified despite the failure:\n%s", filename, string(result))
+		}
+	}
+	assertNoTempFiles(t, tempDir)
+}
+
+func TestTransactionAbortsWhenFileChangedBeforeCommit(t *testing.T) {
+	tempDir := t.TempDir()
+	first := filepath.Join(tempDir, "a.blade.php")
+	second := filepath.Join(tempDir, "b.blade.php")
+	for _, path := range []string{first, second} {
+		if err := os.WriteFile(path, []byte("{{ Form::text('x') }}"), 0644); err != nil {
+			t.Fatalf("Failed to create test file: %v", err)
+		}
+	}
+
+	config := newReplacementConfig()
+	for _, path := range []string{first, second} {
+		result, err := convertFile(path)
+		if err != nil {
+			t.Fatalf("convertFile() error: %v", err)
+		}
+		config.Results = append(config.Results, result)
+	}
+	if err := os.WriteFile(second, []byte("edited"), 0644); err != nil {
+		t.Fatalf("Failed to edit file: %v", err)
+	}
+
+	if err := commitTransaction(config); err == nil {
+		t.Fatal("commitTransaction() should fail")
+	}
+	content, _ := os.ReadFile(first)
+	if string(content) != "{{ Form::text('x') }}" {
+		t.Errorf("first file was modified:\n%s", string(content))
+	}
+	assertNoTempFiles(t, tempDir)
+}
+
+func TestTransactionCommitsAllFiles(t *testing.T) {
+	tempDir := t.TempDir()
+	for _, filename := range []string{"a.blade.php", "b.blade.php"} {
+		if err := os.WriteFile(filepath.Join(tempDir, filename), []byte("{{ Form::text('x') }}"), 0644); err != nil {
+			t.Fatalf("Failed to create test file: %v", err)
+		}
+	}
+
+	config := newReplacementConfig()
+	config.TargetPath = tempDir
+	config.Transaction = true
+	if err := processBladeFiles(config); err != nil {
+		t.Fatalf("processBladeFiles() error: %v", err)
+	}
+	for _, filename := range []string{"a.blade.php", "b.blade.php"} {
+		content, _ := os.ReadFile(filepath.Join(tempDir, filename))
+		if string(content) != `<input type="text" name="x" value="">` {
+			t.Errorf("%s was not converted:\n%s", filename, string(content))
+		}
+	}
+	assertNoTempFiles(t, tempDir)
+}
+
+// assertNoTempFiles は書き込み用の一時ファイルが残っていないことを確認する。
+func assertNoTempFiles(t *testing.T, dir string) {
+	t.Helper()
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("Failed to read dir: %v", err)
+	}
+	for _, entry := range entries {
+		if strings.Contains(entry.Name(), ".ffr-") {
+			t.Errorf("temporary file left behind: %s", entry.Name())
+		}
+	}
+}